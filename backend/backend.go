@@ -0,0 +1,171 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// OrderingBackend contains all consensus specific knowledge of the judge.
+// It decodes the orderer metadata of the ledgers and defines the phases, which verify the signed ordering evidence
+// and the block-cutting rules of its consensus type
+type OrderingBackend interface {
+	// ConsensusType returns the consensus type of the channel config, which the backend handles
+	ConsensusType() string
+
+	// DecodeMetadata decodes the orderer metadata of the ledgers of all peers
+	DecodeMetadata(ledgers []*Ledger) error
+
+	// EvidencePhases returns the phases, which verify the signatures, proofs and metadata proving the ordering
+	EvidencePhases() []*Phase
+
+	// CuttingPhases returns the phases, which verify that the blocks were cut following the rules of the consensus type
+	CuttingPhases() []*Phase
+}
+
+// ResumableBackend is implemented by the backends, whose checks can continue from the checkpoint of a previous run
+// instead of verifying the ledgers from the genesis block again
+type ResumableBackend interface {
+	OrderingBackend
+
+	// CheckpointState returns the state of the checks of ledger i after all blocks below the given height were verified
+	CheckpointState(i int, height uint64) (*CheckpointState, error)
+
+	// Resume continues the checks of ledger i with the state of the checkpoint at the given height.
+	// It is called after DecodeMetadata, which received the ledgers without the blocks verified by the previous run
+	Resume(i int, height uint64, state *CheckpointState) error
+}
+
+// CheckpointState is the state of the checks of a single ledger, which is carried over to the next run
+type CheckpointState struct {
+	// NextKafkaOffset is the offset, with which the Kafka sequence continues after the checkpoint
+	NextKafkaOffset int64 `json:"next_kafka_offset"`
+	// PendingOffsets are the offsets of the batch, which was still pending in the Block-Cutting algorithm.
+	// They were cut into the last block for a cause, which was not part of the ledger yet
+	PendingOffsets []int64 `json:"pending_offsets,omitempty"`
+	// Envelopes are all envelopes up to the checkpoint, such that replays across the checkpoint are detected
+	Envelopes []*validator.EnvelopeRecord `json:"envelopes,omitempty"`
+	// OpenMerkleLeaves are the leaves of the Merkle batches, which are not complete at the checkpoint and may be continued by later blocks
+	OpenMerkleLeaves []*validator.MerkleLeafRecord `json:"open_merkle_leaves,omitempty"`
+}
+
+// ResumeHeight returns the number of the first block, which has to be verified when resuming from the checkpoint at the given height.
+// The cut of the last block cannot be verified as long as the batch is pending, thus this block is verified again
+func (s *CheckpointState) ResumeHeight(height uint64) uint64 {
+	if len(s.PendingOffsets) > 0 && height > 1 {
+		return height - 1
+	}
+	return height
+}
+
+// Ledger contains the blocks received by a single peer
+type Ledger struct {
+	Identity string
+	Blocks   []*cb.Block
+}
+
+// Names of the checks, which are run by the phases of the backends. They are recorded in the evidence bundles to identify the check rendering a verdict
+const (
+	CheckKafkaMessages      = "kafka-messages"
+	CheckOffsetEquivocation = "offset-equivocation"
+	CheckTTCMessages        = "ttc-messages"
+	CheckBookkeeping        = "kafka-metadata-bookkeeping"
+	CheckConfigSequences    = "config-sequences"
+	CheckConfigBlocks       = "config-blocks"
+	CheckChannelHeaders     = "channel-headers"
+	CheckCreatorSignatures  = "creator-signatures"
+	CheckKafkaComparison    = "kafka-comparison"
+	CheckMerkleBatches      = "merkle-batches"
+	CheckReplays            = "replays"
+	CheckPeerMetadata       = "peer-metadata"
+	CheckMessageSizes       = "message-sizes"
+	CheckBlockCutting       = "block-cutting"
+	CheckBatchTimeouts      = "batch-timeouts"
+	CheckBlockSignatures    = "block-signatures"
+	CheckRaftMetadata       = "raft-metadata"
+	CheckForks              = "forks"
+	CheckBatchSizes         = "batch-sizes"
+	CheckQuorums            = "quorums"
+	CheckEquivocations      = "equivocations"
+)
+
+// Phase is a single verification step, which the judge runs on behalf of a backend
+type Phase struct {
+	// Check names the check, which is run by the phase
+	Check string
+	// Description is printed before the phase is run
+	Description string
+	// Success is printed if the phase did not render any verdict
+	Success string
+	Run     func() []*verdicts.Verdict
+}
+
+// Options contains the parameters, which are not part of the ledgers and have to be given to the judge
+type Options struct {
+	ChannelName       string
+	KafkaPublicKey    string
+	MaxBatchSize      int
+	PreferredMaxBytes int
+	AbsoluteMaxBytes  int
+	OrdererVersion    string
+	BatchTimeout      time.Duration
+	TimingTolerance   time.Duration
+	// Progress receives the notices of the checks, e.g. skipped checks and the diff of a wrongly cut block. If it is nil, they are dropped
+	Progress func(message string)
+}
+
+func (o *Options) progress(message string) {
+	if o.Progress != nil {
+		o.Progress(message)
+	}
+}
+
+// FillBatchSize replaces the parameters of the Block-Cutting algorithm, which are not given, by the BatchSize of the genesis config.
+// Later config blocks may change the BatchSize, thus explicitly given parameters take precedence
+func (o *Options) FillBatchSize(genesis *cb.Block) error {
+	if o.MaxBatchSize > 0 && o.PreferredMaxBytes > 0 && o.AbsoluteMaxBytes > 0 {
+		return nil
+	}
+	config, err := channelconfig.GetConfigFromBlock(genesis)
+	if err != nil {
+		return err
+	}
+	batchSize, err := channelconfig.GetBatchSize(config)
+	if err != nil {
+		return fmt.Errorf("BatchSize is neither given nor part of the genesis config: %s", err)
+	}
+	if o.MaxBatchSize <= 0 {
+		o.MaxBatchSize = int(batchSize.MaxMessageCount)
+		o.progress("MaxBatchSize is not given, using " + strconv.Itoa(o.MaxBatchSize) + " of the genesis config")
+	}
+	if o.PreferredMaxBytes <= 0 {
+		o.PreferredMaxBytes = int(batchSize.PreferredMaxBytes)
+		o.progress("PreferredMaxBytes is not given, using " + strconv.Itoa(o.PreferredMaxBytes) + " of the genesis config")
+	}
+	if o.AbsoluteMaxBytes <= 0 {
+		o.AbsoluteMaxBytes = int(batchSize.AbsoluteMaxBytes)
+		o.progress("AbsoluteMaxBytes is not given, using " + strconv.Itoa(o.AbsoluteMaxBytes) + " of the genesis config")
+	}
+	if o.MaxBatchSize <= 0 || o.PreferredMaxBytes <= 0 {
+		return fmt.Errorf("BatchSize of the genesis config does not limit the number and size of the messages of a block")
+	}
+	return nil
+}
+
+// NewBackend creates the backend of the given consensus type
+func NewBackend(consensusType string, options *Options) (OrderingBackend, error) {
+	switch consensusType {
+	case "kafka":
+		return NewKafkaBackend(options)
+	case "etcdraft":
+		return NewRaftBackend(), nil
+	case "BFT":
+		return NewBFTBackend(), nil
+	}
+	return nil, fmt.Errorf("Unsupported consensus type %s", consensusType)
+}
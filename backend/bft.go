@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"github.com/hyperledger/fabric_judge/bft"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// BFTBackend verifies ledgers of channels, which are ordered by a BFT ordering service.
+// Each block has to carry the signatures of a quorum of consenters, which are read from the channel config
+type BFTBackend struct {
+	verifiers []*bft.Verifier
+}
+
+// NewBFTBackend creates a backend for BFT channels
+func NewBFTBackend() *BFTBackend {
+	return &BFTBackend{}
+}
+
+// ConsensusType returns "BFT"
+func (b *BFTBackend) ConsensusType() string {
+	return "BFT"
+}
+
+// DecodeMetadata prepares the verifiers of all ledgers. The quorum signatures are decoded when they are verified
+func (b *BFTBackend) DecodeMetadata(ledgers []*Ledger) error {
+	b.verifiers = make([]*bft.Verifier, 0, len(ledgers))
+	for _, ledger := range ledgers {
+		b.verifiers = append(b.verifiers, bft.NewVerifier(ledger.Blocks, ledger.Identity))
+	}
+	return nil
+}
+
+// EvidencePhases verifies the quorum signatures of every ledger and searches for conflicting quorum-signed blocks.
+// Two quorums always intersect, thus conflicting quorum-signed blocks prove which consenters equivocated
+func (b *BFTBackend) EvidencePhases() []*Phase {
+	return []*Phase{
+		{
+			Check:       CheckQuorums,
+			Description: "Verifying the hash chain and the quorum signatures of all blocks",
+			Success:     "All blocks are chained and signed by a quorum of consenters",
+			Run: func() []*verdicts.Verdict {
+				for _, v := range b.verifiers {
+					if verdict := v.VerifyQuorums(); verdict != nil {
+						return verdict
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Check:       CheckEquivocations,
+			Description: "Comparing the ledgers of all peers to find conflicting quorum-signed blocks",
+			Success:     "Ledgers of all peers are identical",
+			Run: func() []*verdicts.Verdict {
+				return bft.FindEquivocations(b.verifiers...)
+			},
+		},
+	}
+}
+
+// CuttingPhases returns no phases, since the block-cutting of a BFT ordering service is agreed upon by the quorum
+func (b *BFTBackend) CuttingPhases() []*Phase {
+	return nil
+}
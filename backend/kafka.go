@@ -0,0 +1,310 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric_judge/comparator"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// KafkaBackend verifies ledgers of channels, which are ordered by the Kafka-based orderer adding Kafka merkle proofs and signatures to every message
+type KafkaBackend struct {
+	options        *Options
+	sizeAccounting validator.SizeAccounting
+	verifiers      []*validator.Verifier
+}
+
+// NewKafkaBackend creates a backend for Kafka channels. The byte accounting of the blockcutter is chosen by the given orderer version
+func NewKafkaBackend(options *Options) (*KafkaBackend, error) {
+	if options.KafkaPublicKey == "" {
+		return nil, fmt.Errorf("Kafka channels can only be judged with the public key of the Kafka Cluster")
+	}
+	sizeAccounting, err := validator.SizeAccountingOfVersion(options.OrdererVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaBackend{
+		options:        options,
+		sizeAccounting: sizeAccounting,
+	}, nil
+}
+
+// ConsensusType returns "kafka"
+func (b *KafkaBackend) ConsensusType() string {
+	return "kafka"
+}
+
+// DecodeMetadata decodes the KafkaMetadata of all blocks
+func (b *KafkaBackend) DecodeMetadata(ledgers []*Ledger) error {
+	b.verifiers = make([]*validator.Verifier, 0, len(ledgers))
+	for _, ledger := range ledgers {
+		v, err := validator.NewVerifier(ledger.Blocks, b.options.KafkaPublicKey, ledger.Identity, b.options.MaxBatchSize, b.options.PreferredMaxBytes, b.options.AbsoluteMaxBytes, b.sizeAccounting)
+		if err != nil {
+			return err
+		}
+		v.Progress = b.options.Progress
+		b.verifiers = append(b.verifiers, v)
+	}
+	return nil
+}
+
+// EvidencePhases verifies the Kafka merkle proofs and signatures, the metadata written by the orderers and the contents of the ordered envelopes
+func (b *KafkaBackend) EvidencePhases() []*Phase {
+	return []*Phase{
+		// Verify all merkle proofs, kafka signatures and whether the sequence numbers are incremented sequentially
+		// Here, there are two possible verdicts:
+		// 1. 	Peer accepts block containing invalid merkle proofs, kafka signatures or inconsistent seq. numbers
+		// 		In this case, we blame both orderer and peer
+		// 2. 	Inconsistency is only shown in the last block:
+		// 		Here we assume, that the peer actually followed the protocol and shut down after receiving an invalid kafka message.
+		{
+			Check:       CheckKafkaMessages,
+			Description: "Verifying Merkle-Proofs and signatures of all Kafka messages. Furthermore, we verify that the Kafka sequence numbers are sorted correctly",
+			Success:     "Verification successfully complete",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyKafkaMessages()
+			}),
+		},
+		// A single ledger can already prove that Kafka equivocated, if the same offset was signed with different contents
+		// or if consecutive signed batches overlap. Here, no second peer is involved
+		{
+			Check:       CheckOffsetEquivocation,
+			Description: "Searching each ledger for offsets that Kafka signed more than once",
+			Success:     "No offset equivocation was found",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyOffsetEquivocation()
+			}),
+		},
+		// The TTC and connect messages are fully decoded, such that we can check that each TTC message cut the block it was posted for,
+		// stale TTC messages were ignored and connect messages do not carry any payload
+		{
+			Check:       CheckTTCMessages,
+			Description: "Verifying the semantics of all TTC and connect messages",
+			Success:     "All TTC and connect messages were handled correctly",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyConnectAndTTCMessages()
+			}),
+		},
+		// The orderer records the offsets it has processed in the KafkaMetadata of every block, which must match the contents of the ledger
+		{
+			Check:       CheckBookkeeping,
+			Description: "Verifying the offsets recorded in the KafkaMetadata of all blocks",
+			Success:     "KafkaMetadata offsets are consistent with the ledger",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyKafkaMetadataBookkeeping()
+			}),
+		},
+		// Messages validated against a stale config sequence must be re-validated and re-submitted by the orderer.
+		// Thus, we track the config sequence through the ledger and check that no stale or duplicated message was committed
+		{
+			Check:       CheckConfigSequences,
+			Description: "Verifying the config sequences of all committed messages",
+			Success:     "All committed messages were validated against the current config sequence",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyConfigSequences()
+			}),
+		},
+		// Config messages must be isolated in their own block and the LAST_CONFIG metadata must always point at the most recent config block.
+		// Here, we do not trust the IsConfigMessage flag written by the orderer, but inspect the envelopes themselves
+		{
+			Check:       CheckConfigBlocks,
+			Description: "Verifying the isolation of config messages and the LAST_CONFIG metadata",
+			Success:     "Config messages were isolated and LAST_CONFIG metadata is correct",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyConfigBlocks()
+			}),
+		},
+		// The orderer must not smuggle cross-channel or malformed transactions into the ledger, thus we check the channel header of every envelope
+		{
+			Check:       CheckChannelHeaders,
+			Description: "Verifying the channel headers of all ordered envelopes",
+			Success:     "All channel headers are valid",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyChannelHeaders(b.options.ChannelName, validator.DefaultAllowedHeaderTypes, validator.DefaultTimestampWindow)
+			}),
+		},
+		// A colluding orderer and Kafka Cluster could order transactions which no client ever signed.
+		// Thus, we verify the creator signature of every envelope with an offline MSP built from the channel config
+		{
+			Check:       CheckCreatorSignatures,
+			Description: "Verifying the creator signatures of all ordered envelopes",
+			Success:     "All envelopes are properly signed by their creators",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyCreatorSignatures()
+			}),
+		},
+		// Here we can assume, that all peers received the kafka messages in the intended order (because kafka seq. numbers are sorted sequentially)
+		// Thus we can now check, if the Kafka Cluster (viewed as a single entity) signed two different messages with the same sequence number
+		// In this case, we obviously render a verdict against the Kafka Cluster
+		{
+			Check:       CheckKafkaComparison,
+			Description: "Comparing Kafka messages of all ledgers to check, if the same sequence number was used on different blocks",
+			Success:     "No irregularity was found",
+			Run:         b.compareLedgers,
+		},
+		// Each Merkle proof was verified on its own, thus we additionally check that all proofs under the same signed root are consistent with each other
+		{
+			Check:       CheckMerkleBatches,
+			Description: "Verifying that all Merkle proofs under the same signed root are consistent",
+			Success:     "All Merkle proofs are consistent",
+			Run: func() []*verdicts.Verdict {
+				return validator.VerifyMerkleBatches(b.verifiers...)
+			},
+		},
+		// Furthermore, the same envelope must not be ordered at different offsets and the same tx_id must not be used for different payloads
+		{
+			Check:       CheckReplays,
+			Description: "Searching all ledgers for replayed envelopes and reused tx_ids",
+			Success:     "No envelope was replayed",
+			Run: func() []*verdicts.Verdict {
+				return comparator.NewReplayDetector(b.verifiers...).DetectReplays()
+			},
+		},
+		// Besides the ordering, the peers write the validation flags and the commit hash of every block, which must be identical on all honest peers
+		{
+			Check:       CheckPeerMetadata,
+			Description: "Comparing the validation flags and commit hashes of all peers",
+			Success:     "All peers validated and committed the blocks equally",
+			Run: func() []*verdicts.Verdict {
+				return comparator.NewPeerComparator(b.verifiers...).ComparePeerMetadata()
+			},
+		},
+	}
+}
+
+// CuttingPhases replays the Kafka stream through the Block-Cutting algorithm and verifies the timing of the TTC messages
+func (b *KafkaBackend) CuttingPhases() []*Phase {
+	phases := []*Phase{
+		// Before replaying the Block-Cutting algorithm, we check the size of every ordered envelope:
+		// The orderer must reject envelopes exceeding AbsoluteMaxBytes and isolate envelopes exceeding PreferredMaxBytes
+		{
+			Check:       CheckMessageSizes,
+			Description: "Verifying the size of all ordered envelopes",
+			Success:     "All envelopes respect the size limits",
+			Run: func() []*verdicts.Verdict {
+				if b.options.AbsoluteMaxBytes <= 0 {
+					b.options.progress("AbsoluteMaxBytes is not given, skipping the AbsoluteMaxBytes check")
+				}
+				return b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+					return v.VerifyMessageSizes()
+				})()
+			},
+		},
+		// At this point, the only thing left to do is to verify that the orderer cut his blocks according to the given Block-Cutting algorithm
+		{
+			Check:       CheckBlockCutting,
+			Description: "Verifying that the orderer has cut the blocks correctly",
+			Success:     "Orderer cut blocks following the Block-Cutting algorithm",
+			Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyBlockCuttingOfOrderer()
+			}),
+		},
+	}
+
+	// Finally, we use the Kafka timestamps to check whether the TTC messages were posted once the BatchTimeout expired
+	// Here, the verdicts belong to the timing category, since they depend on the given tolerance
+	if b.options.BatchTimeout <= 0 {
+		b.options.progress("BatchTimeout is not given, skipping the verification of the TTC timing")
+		return phases
+	}
+
+	return append(phases, &Phase{
+		Check:       CheckBatchTimeouts,
+		Description: "Verifying the timing of the TTC messages using the Kafka timestamps",
+		Success:     "All TTC messages were posted in time",
+		Run: b.forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+			return v.VerifyBatchTimeouts(b.options.BatchTimeout, b.options.TimingTolerance)
+		}),
+	})
+}
+
+// CheckpointState returns the next Kafka offset, the pending batch, the envelopes and the open Merkle batches of ledger i after the blocks below the given height
+func (b *KafkaBackend) CheckpointState(i int, height uint64) (*CheckpointState, error) {
+	v := b.verifiers[i]
+	k := 0
+	for k < len(v.BlockNumbers) && v.BlockNumbers[k] < height {
+		k++
+	}
+	if k == 0 {
+		return nil, fmt.Errorf("Ledger of %s does not contain any block below height %d", v.Identity, height)
+	}
+
+	prefix, err := validator.NewVerifier(v.Blocks[:k], b.options.KafkaPublicKey, v.Identity, b.options.MaxBatchSize, b.options.PreferredMaxBytes, b.options.AbsoluteMaxBytes, b.sizeAccounting)
+	if err != nil {
+		return nil, err
+	}
+	prefix.ResumedEnvelopes = v.ResumedEnvelopes
+	prefix.ResumedMerkleLeaves = v.ResumedMerkleLeaves
+	nextOffset, err := prefix.NextKafkaOffset()
+	if err != nil {
+		return nil, err
+	}
+	pendingOffsets, err := prefix.PendingOffsets()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckpointState{
+		NextKafkaOffset:  nextOffset,
+		PendingOffsets:   pendingOffsets,
+		Envelopes:        prefix.EnvelopeRecords(),
+		OpenMerkleLeaves: prefix.OpenMerkleLeaves(),
+	}, nil
+}
+
+// Resume continues the Kafka sequence of ledger i with the offset of the checkpoint, and the search for replays and inconsistent Merkle batches
+// with the envelopes and open batches of the checkpoint. If the last block of the checkpoint is verified again, it has to consist of the pending batch of the checkpoint
+func (b *KafkaBackend) Resume(i int, height uint64, state *CheckpointState) error {
+	v := b.verifiers[i]
+	v.ResumedEnvelopes = state.Envelopes
+	v.ResumedMerkleLeaves = state.OpenMerkleLeaves
+	from := state.ResumeHeight(height)
+	if from == height {
+		v.ExpectedOffsets[height] = state.NextKafkaOffset
+	}
+
+	for k, number := range v.BlockNumbers {
+		if number != from || from == height {
+			continue
+		}
+		offsets := make([]int64, 0, len(v.Envelopes[k]))
+		for _, env := range v.Envelopes[k] {
+			if env.KafkaPayload != nil {
+				offsets = append(offsets, env.KafkaPayload.KafkaOffset)
+			}
+		}
+		if !validator.EqualOffsets(offsets, state.PendingOffsets) {
+			return fmt.Errorf("Block %d of %s does not consist of the pending batch of the checkpoint", number, v.Identity)
+		}
+	}
+	return nil
+}
+
+// compareLedgers compares the Kafka messages and metadata of every pair of ledgers
+func (b *KafkaBackend) compareLedgers() []*verdicts.Verdict {
+	for i := 0; i < len(b.verifiers); i++ {
+		for j := i + 1; j < len(b.verifiers); j++ {
+			kafkaComparator := comparator.NewKafkaComparator(b.verifiers[i], b.verifiers[j])
+			if verdict := kafkaComparator.CompareKafkaMessages(); verdict != nil {
+				return verdict
+			}
+			if verdict := kafkaComparator.CompareKafkaMetadataBookkeeping(); verdict != nil {
+				return verdict
+			}
+		}
+	}
+	return nil
+}
+
+// forEachVerifier runs the check on the verifiers one after another, until one of them renders a verdict
+func (b *KafkaBackend) forEachVerifier(check func(v *validator.Verifier) []*verdicts.Verdict) func() []*verdicts.Verdict {
+	return func() []*verdicts.Verdict {
+		for _, v := range b.verifiers {
+			if verdict := check(v); verdict != nil {
+				return verdict
+			}
+		}
+		return nil
+	}
+}
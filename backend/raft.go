@@ -0,0 +1,92 @@
+package backend
+
+import (
+	"github.com/hyperledger/fabric_judge/raft"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// RaftBackend verifies ledgers of channels, which are ordered by etcdraft.
+// Without Kafka signatures, the orderer signatures and the hash chain are the only proof that a block was produced by the ordering service
+type RaftBackend struct {
+	verifiers []*raft.Verifier
+}
+
+// NewRaftBackend creates a backend for etcdraft channels. The BatchSize and the consenters are read from the channel config
+func NewRaftBackend() *RaftBackend {
+	return &RaftBackend{}
+}
+
+// ConsensusType returns "etcdraft"
+func (b *RaftBackend) ConsensusType() string {
+	return "etcdraft"
+}
+
+// DecodeMetadata decodes the etcdraft metadata of all blocks
+func (b *RaftBackend) DecodeMetadata(ledgers []*Ledger) error {
+	b.verifiers = make([]*raft.Verifier, 0, len(ledgers))
+	for _, ledger := range ledgers {
+		v, err := raft.NewVerifier(ledger.Blocks, ledger.Identity)
+		if err != nil {
+			return err
+		}
+		b.verifiers = append(b.verifiers, v)
+	}
+	return nil
+}
+
+// EvidencePhases verifies the hash chain, the orderer signatures and the etcdraft metadata of every ledger and searches for the first fork
+func (b *RaftBackend) EvidencePhases() []*Phase {
+	return []*Phase{
+		{
+			Check:       CheckBlockSignatures,
+			Description: "Verifying the hash chain and the orderer signatures of all blocks",
+			Success:     "All blocks are chained and signed by an orderer",
+			Run: b.forEachVerifier(func(v *raft.Verifier) []*verdicts.Verdict {
+				return v.VerifyBlockSignatures()
+			}),
+		},
+		{
+			Check:       CheckRaftMetadata,
+			Description: "Verifying the etcdraft metadata of all blocks",
+			Success:     "Raft indices and consenter ids are consistent with the channel config",
+			Run: b.forEachVerifier(func(v *raft.Verifier) []*verdicts.Verdict {
+				return v.VerifyRaftMetadata()
+			}),
+		},
+		{
+			Check:       CheckForks,
+			Description: "Comparing the ledgers of all peers to find the first fork",
+			Success:     "Ledgers of all peers are identical",
+			Run: func() []*verdicts.Verdict {
+				return raft.FindFirstFork(b.verifiers...)
+			},
+		},
+	}
+}
+
+// CuttingPhases verifies the blocks against the BatchSize of the channel config.
+// Raft does not persist the received messages, thus the blocks can only be checked against the limits of the BatchSize
+func (b *RaftBackend) CuttingPhases() []*Phase {
+	return []*Phase{
+		{
+			Check:       CheckBatchSizes,
+			Description: "Verifying the blocks against the BatchSize of the channel config",
+			Success:     "All blocks respect the BatchSize",
+			Run: b.forEachVerifier(func(v *raft.Verifier) []*verdicts.Verdict {
+				return v.VerifyBatchSizes()
+			}),
+		},
+	}
+}
+
+// forEachVerifier runs the check on the verifiers one after another, until one of them renders a verdict
+func (b *RaftBackend) forEachVerifier(check func(v *raft.Verifier) []*verdicts.Verdict) func() []*verdicts.Verdict {
+	return func() []*verdicts.Verdict {
+		for _, v := range b.verifiers {
+			if verdict := check(v); verdict != nil {
+				return verdict
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package bft
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// blockVersion is one of several different blocks, which the peers committed at the same height
+type blockVersion struct {
+	headerHash []byte
+	verifiers  []*Verifier
+	signers    []*cb.Consenter
+	quorum     bool
+}
+
+// FindEquivocations compares the ledgers of all peers block by block and returns verdicts for the first height, at which they diverge.
+// Since any two quorums intersect, two different blocks at the same height, which are both signed by a quorum, prove that the consenters in the intersection equivocated.
+// Every consenter which signed both sides is named. A block without a quorum is blamed on the peers which committed it
+func FindEquivocations(verifiers ...*Verifier) []*verdicts.Verdict {
+	height := -1
+	for _, v := range verifiers {
+		if height == -1 || len(v.Blocks) < height {
+			height = len(v.Blocks)
+		}
+	}
+
+	for i := 0; i < height; i++ {
+		versions := make([]*blockVersion, 0)
+		for _, v := range verifiers {
+			headerHash := validator.BlockHeaderHash(v.Blocks[i].Header)
+			found := false
+			for _, version := range versions {
+				if bytes.Equal(version.headerHash, headerHash) {
+					version.verifiers = append(version.verifiers, v)
+					found = true
+					break
+				}
+			}
+			if !found {
+				versions = append(versions, &blockVersion{headerHash: headerHash, verifiers: []*Verifier{v}})
+			}
+		}
+
+		if len(versions) > 1 {
+			return blameEquivocation(i, versions)
+		}
+	}
+
+	return nil
+}
+
+func blameEquivocation(i int, versions []*blockVersion) []*verdicts.Verdict {
+	var result []*verdicts.Verdict
+
+	for _, version := range versions {
+		version.signers, version.quorum = version.verifiers[0].quorumSignersOfBlock(i)
+		if version.quorum {
+			continue
+		}
+		for _, v := range version.verifiers {
+			result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Peer committed block %d with header hash %s, which is not signed by a quorum and differs from the block of other peers", v.BlockNumbers[i], hex.EncodeToString(version.headerHash)), v.Identity, 2))
+		}
+	}
+
+	blockNumber := versions[0].verifiers[0].BlockNumbers[i]
+
+	// every consenter is named once, even if it signed more than two versions
+	named := make(map[uint32]bool)
+	for a := 0; a < len(versions); a++ {
+		for b := a + 1; b < len(versions); b++ {
+			if !versions[a].quorum || !versions[b].quorum {
+				continue
+			}
+			for _, consenter := range versions[a].signers {
+				if named[consenter.Id] || !containsConsenter(versions[b].signers, consenter) {
+					continue
+				}
+				named[consenter.Id] = true
+				result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Orderer signed the conflicting blocks %s and %s at height %d", hex.EncodeToString(versions[a].headerHash), hex.EncodeToString(versions[b].headerHash), blockNumber), consenterName(consenter), 1))
+			}
+		}
+	}
+
+	// block i of every version is referenced as evidence
+	for _, version := range versions {
+		verdicts.AttachEvidence(result, version.verifiers[0].Identity, version.verifiers[0].BlockNumbers[i])
+	}
+	return result
+}
+
+// quorumSignersOfBlock returns the consenters, which signed block i, and whether they form a quorum
+func (v *Verifier) quorumSignersOfBlock(i int) ([]*cb.Consenter, bool) {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks[:i+1])
+	if err != nil {
+		return nil, false
+	}
+	signers, consenters, err := v.consenterSignersOfBlock(i, configs[i])
+	if err != nil || len(signers) < Quorum(len(consenters)) {
+		return nil, false
+	}
+	return signers, true
+}
+
+func containsConsenter(consenters []*cb.Consenter, consenter *cb.Consenter) bool {
+	for _, c := range consenters {
+		if c.Id == consenter.Id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package bft_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/bft"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestFindEquivocations(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"})
+	if verdict := bft.FindEquivocations(bft.NewVerifier(blocks, "peerA"), bft.NewVerifier(blocks, "peerB")); len(verdict) != 0 {
+		t.Fatalf("Expected the peers committing the same blocks to pass, got: %s", verdict[0].Message())
+	}
+
+	// orderers 2 and 3 sign both blocks at height 2, each together with a quorum
+	first := append(blocks[:2:2], f.Block(blocks[1], f.Consenters[:3], "tx2"))
+	second := append(blocks[:2:2], f.Block(blocks[1], f.Consenters[1:], "other"))
+	verdict := bft.FindEquivocations(bft.NewVerifier(first, "peerA"), bft.NewVerifier(second, "peerB"))
+	if len(verdict) != 2 {
+		t.Fatalf("Expected the two consenters in the intersection to be blamed, got %d verdicts", len(verdict))
+	}
+	for k, id := range []string{"orderer2:7050 (consenter 2 of OrdererMSP)", "orderer3:7050 (consenter 3 of OrdererMSP)"} {
+		if verdict[k].Identity() != id || !strings.HasPrefix(verdict[k].Message(), "Orderer signed the conflicting blocks") {
+			t.Errorf("Expected %s to be blamed for signing both blocks, got %s: %s", id, verdict[k].Identity(), verdict[k].Message())
+		}
+	}
+}
+
+func TestFindEquivocationsWithoutQuorum(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"})
+
+	// peerB commits a different block, which only a single consenter signed
+	forged := append(blocks[:1:1], f.Block(blocks[0], []*ledgertest.Orderer{f.Consenters[0]}, "other"))
+	verdict := bft.FindEquivocations(bft.NewVerifier(blocks, "peerA"), bft.NewVerifier(forged, "peerB"))
+	if len(verdict) != 1 || verdict[0].Identity() != "peerB" || !strings.Contains(verdict[0].Message(), "which is not signed by a quorum") {
+		t.Fatalf("Expected peerB to be blamed for the block without quorum, got %v", verdict)
+	}
+}
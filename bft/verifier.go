@@ -0,0 +1,120 @@
+package bft
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	"github.com/hyperledger/fabric_judge/msp"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// Verifier contains the blocks received by a single peer from a BFT ordering service
+type Verifier struct {
+	Blocks       []*cb.Block
+	BlockNumbers []uint64
+	Identity     string
+}
+
+// NewVerifier creates a Verifier for the given blocks
+func NewVerifier(blocks []*cb.Block, identity string) *Verifier {
+	verifier := &Verifier{
+		Blocks:       blocks,
+		BlockNumbers: make([]uint64, 0),
+		Identity:     identity,
+	}
+
+	for _, block := range blocks {
+		verifier.BlockNumbers = append(verifier.BlockNumbers, block.Header.Number)
+	}
+
+	return verifier
+}
+
+// Quorum returns the number of consenters, which have to sign a block in a BFT ordering service of n consenters tolerating f = (n-1)/3 faults.
+// Any two quorums intersect in at least f+1 consenters, thus in at least one correct consenter
+func Quorum(n int) int {
+	f := (n - 1) / 3
+	return (n + f + 2) / 2
+}
+
+// VerifyQuorums checks that the blocks form a hash chain and that every block is signed by a quorum of the consenters in effect.
+// The peer must not have committed a block violating either property, thus the verdicts are rendered against the peer
+func (v *Verifier) VerifyQuorums() []*verdicts.Verdict {
+	invalid, err := validator.VerifyHashChain(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 2).WithEvidence(v.Identity, validator.HashChainEvidence(v.Blocks, invalid)...)}
+	}
+
+	return v.VerifyQuorumSignatures()
+}
+
+// VerifyQuorumSignatures checks that every block is signed by a quorum of the consenters in effect, without verifying the hash chain
+func (v *Verifier) VerifyQuorumSignatures() []*verdicts.Verdict {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	// starting at i = 1, since the genesis block is not signed by the orderers
+	for i := 1; i < len(v.Blocks); i++ {
+		signers, consenters, err := v.consenterSignersOfBlock(i, configs[i])
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+		}
+		if len(signers) < Quorum(len(consenters)) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Peer committed block %d, which is signed by %d of %d consenters, but a quorum of %d is required", v.BlockNumbers[i], len(signers), len(consenters), Quorum(len(consenters))), v.Identity, 2).WithEvidence(v.Identity, v.BlockNumbers[i])}
+		}
+	}
+
+	return nil
+}
+
+// consenterSignersOfBlock returns the consenters of the given config in effect for block i, which validly signed it, together with all of its consenters
+func (v *Verifier) consenterSignersOfBlock(i int, config *cb.Config) ([]*cb.Consenter, []*cb.Consenter, error) {
+	consenters, err := channelconfig.GetBFTConsenters(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to read the consenters in effect for block %d: %s", v.BlockNumbers[i], err)
+	}
+	mspManager, err := msp.NewManagerFromConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to build the MSPs in effect for block %d: %s", v.BlockNumbers[i], err)
+	}
+
+	identities, err := validator.GetOrdererSignersOfBlock(v.Blocks[i], mspManager)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signers := make([]*cb.Consenter, 0)
+	for _, consenter := range consenters {
+		for _, identity := range identities {
+			if isConsenter(identity, consenter) {
+				signers = append(signers, consenter)
+				break
+			}
+		}
+	}
+
+	return signers, consenters, nil
+}
+
+// isConsenter checks whether the signing identity is the one of the consenter in the channel config
+func isConsenter(identity *msp.Identity, consenter *cb.Consenter) bool {
+	if identity.MSPID != consenter.MspId {
+		return false
+	}
+	block, _ := pem.Decode(consenter.Identity)
+	if block == nil {
+		return false
+	}
+	return bytes.Equal(block.Bytes, identity.Certificate.Raw)
+}
+
+// consenterName identifies the consenter in the verdicts
+func consenterName(consenter *cb.Consenter) string {
+	return fmt.Sprintf("%s:%d (consenter %d of %s)", consenter.Host, consenter.Port, consenter.Id, consenter.MspId)
+}
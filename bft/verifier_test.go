@@ -0,0 +1,44 @@
+package bft_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/bft"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestQuorum(t *testing.T) {
+	for n, quorum := range map[int]int{1: 1, 3: 2, 4: 3, 5: 4, 7: 5, 10: 7} {
+		if bft.Quorum(n) != quorum {
+			t.Errorf("Expected a quorum of %d of %d consenters, got %d", quorum, n, bft.Quorum(n))
+		}
+	}
+}
+
+func TestVerifyQuorums(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"})
+	blocks = append(blocks, f.Block(blocks[1], f.Consenters[1:], "tx2"))
+	if verdict := bft.NewVerifier(blocks, "peerA").VerifyQuorums(); len(verdict) != 0 {
+		t.Fatalf("Expected the blocks signed by a quorum to pass, got: %s", verdict[0].Message())
+	}
+
+	// two signatures of the same consenter are counted once
+	blocks = append(blocks, f.Block(blocks[2], append(f.Consenters[:2:2], f.Consenters[1]), "tx3"))
+	verdict := bft.NewVerifier(blocks, "peerA").VerifyQuorums()
+	if len(verdict) != 1 || verdict[0].Identity() != "peerA" || verdict[0].Message() != "Peer committed block 3, which is signed by 2 of 4 consenters, but a quorum of 3 is required" {
+		t.Fatalf("Expected the peer to be blamed for the block without quorum, got %v", verdict)
+	}
+}
+
+func TestVerifyQuorumsBrokenHashChain(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"}, []string{"tx2"})
+
+	// the envelopes of block 1 are replaced after the consenters signed its header
+	blocks[1].Data.Data = blocks[2].Data.Data
+	verdict := bft.NewVerifier(blocks, "peerA").VerifyQuorums()
+	if len(verdict) != 1 || verdict[0].Identity() != "peerA" || verdict[0].Message() != "Peer committed block 1, whose DataHash does not match its envelopes" {
+		t.Fatalf("Expected the peer to be blamed for the broken hash chain, got %v", verdict)
+	}
+}
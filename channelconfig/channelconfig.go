@@ -0,0 +1,174 @@
+package channelconfig
+
+import (
+	"fmt"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	ob "github.com/hyperledger/fabric_judge/protos/orderer"
+	"github.com/hyperledger/fabric_judge/protos/orderer/etcdraft"
+)
+
+// ApplicationGroupKey is the key of the group containing the application organizations
+const ApplicationGroupKey = "Application"
+
+// OrdererGroupKey is the key of the group containing the orderer organizations and the ordering parameters
+const OrdererGroupKey = "Orderer"
+
+// GetConfigFromBlock unmarshals the channel config contained in the given config block
+func GetConfigFromBlock(block *cb.Block) (*cb.Config, error) {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		return nil, fmt.Errorf("config block must contain exactly one envelope")
+	}
+
+	env := &cb.Envelope{}
+	err := proto.Unmarshal(block.Data.Data[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(env.Payload, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	configEnvelope := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, configEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, fmt.Errorf("config envelope does not contain a channel group")
+	}
+
+	return configEnvelope.Config, nil
+}
+
+// GetConsensusType returns the consensus type of the ordering service, e.g. "kafka" or "etcdraft"
+func GetConsensusType(config *cb.Config) (*ob.ConsensusType, error) {
+	consensusType := &ob.ConsensusType{}
+	err := unmarshalOrdererValue(config, "ConsensusType", consensusType)
+	if err != nil {
+		return nil, err
+	}
+	return consensusType, nil
+}
+
+// GetBatchSize returns the BatchSize parameters of the channel
+func GetBatchSize(config *cb.Config) (*ob.BatchSize, error) {
+	batchSize := &ob.BatchSize{}
+	err := unmarshalOrdererValue(config, "BatchSize", batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return batchSize, nil
+}
+
+// GetBatchTimeout returns the BatchTimeout of the channel
+func GetBatchTimeout(config *cb.Config) (time.Duration, error) {
+	batchTimeout := &ob.BatchTimeout{}
+	err := unmarshalOrdererValue(config, "BatchTimeout", batchTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(batchTimeout.Timeout)
+}
+
+// GetRaftConfigMetadata returns the consenters and options of an etcdraft ordering service
+func GetRaftConfigMetadata(config *cb.Config) (*etcdraft.ConfigMetadata, error) {
+	consensusType, err := GetConsensusType(config)
+	if err != nil {
+		return nil, err
+	}
+	if consensusType.Type != "etcdraft" {
+		return nil, fmt.Errorf("consensus type is %s instead of etcdraft", consensusType.Type)
+	}
+
+	configMetadata := &etcdraft.ConfigMetadata{}
+	err = proto.Unmarshal(consensusType.Metadata, configMetadata)
+	if err != nil {
+		return nil, err
+	}
+	return configMetadata, nil
+}
+
+// GetBFTConsenters returns the consenters of a BFT ordering service, which are listed in the Orderers value of the orderer group
+func GetBFTConsenters(config *cb.Config) ([]*cb.Consenter, error) {
+	orderers := &cb.Orderers{}
+	err := unmarshalOrdererValue(config, "Orderers", orderers)
+	if err != nil {
+		return nil, err
+	}
+	if len(orderers.ConsenterMapping) == 0 {
+		return nil, fmt.Errorf("channel config does not contain any consenters")
+	}
+	return orderers.ConsenterMapping, nil
+}
+
+func unmarshalOrdererValue(config *cb.Config, key string, msg proto.Message) error {
+	ordererGroup, ok := config.ChannelGroup.Groups[OrdererGroupKey]
+	if !ok {
+		return fmt.Errorf("channel config does not contain an orderer group")
+	}
+	value, ok := ordererGroup.Values[key]
+	if !ok {
+		return fmt.Errorf("orderer group does not contain the value %s", key)
+	}
+	err := proto.Unmarshal(value.Value, msg)
+	if err != nil {
+		return fmt.Errorf("invalid value %s: %s", key, err)
+	}
+	return nil
+}
+
+// GetConfigsInEffect returns the channel config, under which each block of the ledger was ordered.
+// The genesis block is paired with its own config, every other block with the config of the most recent config block before it
+func GetConfigsInEffect(blocks []*cb.Block) ([]*cb.Config, error) {
+	configs := make([]*cb.Config, len(blocks))
+	if len(blocks) == 0 {
+		return configs, nil
+	}
+
+	config, err := GetConfigFromBlock(blocks[0])
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the channel config of the genesis block: %s", err)
+	}
+	configs[0] = config
+
+	for i := 1; i < len(blocks); i++ {
+		configs[i] = config
+		if !IsConfigBlock(blocks[i]) {
+			continue
+		}
+		config, err = GetConfigFromBlock(blocks[i])
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the channel config of config block %d: %s", blocks[i].Header.Number, err)
+		}
+	}
+
+	return configs, nil
+}
+
+// IsConfigBlock checks whether the block contains a config transaction
+func IsConfigBlock(block *cb.Block) bool {
+	for _, data := range block.Data.Data {
+		env := &cb.Envelope{}
+		if proto.Unmarshal(data, env) != nil {
+			continue
+		}
+		payload := &cb.Payload{}
+		if proto.Unmarshal(env.Payload, payload) != nil || payload.Header == nil {
+			continue
+		}
+		channelHeader := &cb.ChannelHeader{}
+		if proto.Unmarshal(payload.Header.ChannelHeader, channelHeader) != nil {
+			continue
+		}
+		if channelHeader.Type == int32(cb.HeaderType_CONFIG) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,7 @@ package comparator
 
 import (
 	"crypto/sha256"
-	"log"
+	"fmt"
 	"reflect"
 
 	cb "github.com/hyperledger/fabric_judge/protos/common"
@@ -16,6 +16,10 @@ type UnwrappedLedgerInfo struct {
 	Envelopes []*cb.Envelope
 	Metadata  []*kf.KafkaMetadata
 	Identity  string
+
+	// EnvelopeBlocks contains the number of the block of each envelope, BlockNumbers the number of the block of each metadata
+	EnvelopeBlocks []uint64
+	BlockNumbers   []uint64
 }
 
 // KafkaComparator contains two instances of UnwrappedLedgerInfo which allows us to compare the Kafka messages
@@ -49,7 +53,8 @@ func (comp *KafkaComparator) CompareKafkaMessages() []*verdicts.Verdict {
 		hash1 := computeHashOfEnvelope(comp.ledgerInfo1.Envelopes[i])
 		hash2 := computeHashOfEnvelope(comp.ledgerInfo2.Envelopes[i])
 		if !reflect.DeepEqual(hash1, hash2) {
-			return []*verdicts.Verdict{verdicts.CreateVerdict("Kafka signed two different messages with the same sequence number", "Kafka Cluster", 0)}
+			verdict := verdicts.CreateVerdict("Kafka signed two different messages with the same sequence number", "Kafka Cluster", 0)
+			return []*verdicts.Verdict{verdict.WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.EnvelopeBlocks[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.EnvelopeBlocks[i])}
 		}
 	}
 
@@ -60,13 +65,18 @@ func (comp *KafkaComparator) CompareKafkaMessages() []*verdicts.Verdict {
 			hash1 := computeHashOfBytes(comp.ledgerInfo1.Metadata[i].TTCPayload.ConsumerMessageBytes)
 			hash2 := computeHashOfBytes(comp.ledgerInfo2.Metadata[i].TTCPayload.ConsumerMessageBytes)
 			if !reflect.DeepEqual(hash1, hash2) {
-				return []*verdicts.Verdict{verdicts.CreateVerdict("Kafka signed two different ttc-messages with the same sequence number", "Kafka Cluster", 0)}
+				verdict := verdicts.CreateVerdict("Kafka signed two different ttc-messages with the same sequence number", "Kafka Cluster", 0)
+				return []*verdicts.Verdict{verdict.WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.BlockNumbers[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.BlockNumbers[i])}
 			}
 		} else if !comp.ledgerInfo1.Metadata[i].ReceivedTTCMessage && !comp.ledgerInfo2.Metadata[i].ReceivedTTCMessage {
 			continue
 		} else {
-			// such an error would already be spotted in the comparison of the envelopes
-			log.Fatal("ERROR: Impossible If-Branch")
+			// equal envelopes imply equal TTC messages, thus one of the orderers recorded a TTC message, which it did not receive
+			msg := fmt.Sprintf("Orderers disagree whether block %d was cut by a TTC message", comp.ledgerInfo1.BlockNumbers[i])
+			return []*verdicts.Verdict{
+				verdicts.CreateVerdict(msg, comp.ledgerInfo1.Identity, 1).WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.BlockNumbers[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.BlockNumbers[i]),
+				verdicts.CreateVerdict(msg, comp.ledgerInfo2.Identity, 1).WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.BlockNumbers[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.BlockNumbers[i]),
+			}
 		}
 	}
 
@@ -74,6 +84,30 @@ func (comp *KafkaComparator) CompareKafkaMessages() []*verdicts.Verdict {
 	return nil
 }
 
+// CompareKafkaMetadataBookkeeping verifies, that both orderers recorded the same KafkaMetadata offsets for the same block
+func (comp *KafkaComparator) CompareKafkaMetadataBookkeeping() []*verdicts.Verdict {
+	minNumberOfBlocks := len(comp.ledgerInfo1.Metadata)
+	if minNumberOfBlocks > len(comp.ledgerInfo2.Metadata) {
+		minNumberOfBlocks = len(comp.ledgerInfo2.Metadata)
+	}
+
+	for i := 0; i < minNumberOfBlocks; i++ {
+		metadata1 := comp.ledgerInfo1.Metadata[i]
+		metadata2 := comp.ledgerInfo2.Metadata[i]
+		if metadata1.LastOffsetPersisted != metadata2.LastOffsetPersisted ||
+			metadata1.LastOriginalOffsetProcessed != metadata2.LastOriginalOffsetProcessed ||
+			metadata1.LastResubmittedConfigOffset != metadata2.LastResubmittedConfigOffset {
+			msg := fmt.Sprintf("Orderers recorded different KafkaMetadata offsets for block %d", comp.ledgerInfo1.BlockNumbers[i])
+			return []*verdicts.Verdict{
+				verdicts.CreateVerdict(msg, comp.ledgerInfo1.Identity, 1).WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.BlockNumbers[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.BlockNumbers[i]),
+				verdicts.CreateVerdict(msg, comp.ledgerInfo2.Identity, 1).WithEvidence(comp.ledgerInfo1.Identity, comp.ledgerInfo1.BlockNumbers[i]).WithEvidence(comp.ledgerInfo2.Identity, comp.ledgerInfo2.BlockNumbers[i]),
+			}
+		}
+	}
+
+	return nil
+}
+
 func computeLedgerInfoFromVerifier(verifier *validator.Verifier) *UnwrappedLedgerInfo {
 	size := 0
 	for i := 0; i < len(verifier.Envelopes); i++ {
@@ -82,13 +116,16 @@ func computeLedgerInfoFromVerifier(verifier *validator.Verifier) *UnwrappedLedge
 
 	ledgerInfo := new(UnwrappedLedgerInfo)
 	ledgerInfo.Envelopes = make([]*cb.Envelope, size)
+	ledgerInfo.EnvelopeBlocks = make([]uint64, size)
 	ledgerInfo.Metadata = verifier.KafkaMetadata
+	ledgerInfo.BlockNumbers = verifier.BlockNumbers
 	ledgerInfo.Identity = verifier.Identity
 
 	height := 0
 	for i := 0; i < len(verifier.Envelopes); i++ {
 		for _, env := range verifier.Envelopes[i] {
 			ledgerInfo.Envelopes[height] = env
+			ledgerInfo.EnvelopeBlocks[height] = verifier.BlockNumbers[i]
 			height++
 		}
 	}
@@ -97,10 +134,7 @@ func computeLedgerInfoFromVerifier(verifier *validator.Verifier) *UnwrappedLedge
 }
 
 func computeHashOfEnvelope(env *cb.Envelope) []byte {
-	h := sha256.New()
-	h.Write(env.Payload)
-	h.Write(env.Signature)
-	return h.Sum(nil)
+	return validator.EnvelopeHash(env)
 }
 
 func computeHashOfBytes(data []byte) []byte {
@@ -0,0 +1,55 @@
+package comparator
+
+import (
+	"bytes"
+
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// signedMessage is a single Kafka message of one ledger, together with the hash of its contents
+type signedMessage struct {
+	identity string
+	block    uint64
+	hash     []byte
+}
+
+// FindOffsetConflicts compares the Kafka messages of all ledgers by their offset instead of their position in the ledger.
+// Thus, ledgers containing different sets of blocks, e.g. the ledgers of an evidence bundle, can be compared as well.
+// Two different messages or TTC messages with the same offset are blamed on the Kafka Cluster, like in CompareKafkaMessages
+func FindOffsetConflicts(verifiers ...*validator.Verifier) []*verdicts.Verdict {
+	messages := make(map[int64]*signedMessage)
+	ttcMessages := make(map[int64]*signedMessage)
+
+	for _, verifier := range verifiers {
+		for i, blockEnv := range verifier.Envelopes {
+			for _, env := range blockEnv {
+				if env.KafkaPayload == nil {
+					continue
+				}
+				msg := &signedMessage{identity: verifier.Identity, block: verifier.BlockNumbers[i], hash: computeHashOfEnvelope(env)}
+				if other := messages[env.KafkaPayload.KafkaOffset]; other != nil && !bytes.Equal(other.hash, msg.hash) {
+					return offsetConflict("Kafka signed two different messages with the same sequence number", other, msg)
+				}
+				messages[env.KafkaPayload.KafkaOffset] = msg
+			}
+
+			if !verifier.KafkaMetadata[i].ReceivedTTCMessage {
+				continue
+			}
+			offset := validator.GetTTCKafkaSeqNrFromMetadata(verifier.KafkaMetadata[i])
+			msg := &signedMessage{identity: verifier.Identity, block: verifier.BlockNumbers[i], hash: computeHashOfBytes(verifier.KafkaMetadata[i].TTCPayload.ConsumerMessageBytes)}
+			if other := ttcMessages[offset]; other != nil && !bytes.Equal(other.hash, msg.hash) {
+				return offsetConflict("Kafka signed two different ttc-messages with the same sequence number", other, msg)
+			}
+			ttcMessages[offset] = msg
+		}
+	}
+
+	return nil
+}
+
+func offsetConflict(msg string, first *signedMessage, second *signedMessage) []*verdicts.Verdict {
+	verdict := verdicts.CreateVerdict(msg, "Kafka Cluster", 0)
+	return []*verdicts.Verdict{verdict.WithEvidence(first.identity, first.block).WithEvidence(second.identity, second.block)}
+}
@@ -0,0 +1,159 @@
+package comparator
+
+import (
+	"bytes"
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// PeerComparator compares the metadata written by the peers, i.e. the validation flags and the commit hashes, across all ledgers
+type PeerComparator struct {
+	verifiers []*validator.Verifier
+}
+
+// NewPeerComparator creates a new instance of PeerComparator
+func NewPeerComparator(verifiers ...*validator.Verifier) *PeerComparator {
+	return &PeerComparator{
+		verifiers: verifiers,
+	}
+}
+
+// ComparePeerMetadata verifies, that all peers validated the transactions of the same block equally and computed the same commit hash.
+// The first divergent block is reported and the peers deviating from the majority are blamed. Without a majority, all peers are blamed
+func (comp *PeerComparator) ComparePeerMetadata() []*verdicts.Verdict {
+	minNumberOfBlocks := len(comp.verifiers[0].Blocks)
+	for _, verifier := range comp.verifiers {
+		if len(verifier.Blocks) < minNumberOfBlocks {
+			minNumberOfBlocks = len(verifier.Blocks)
+		}
+	}
+
+	for i := 0; i < minNumberOfBlocks; i++ {
+		if !comp.sameBlockData(i) {
+			// divergent blocks are ordering faults, which are already covered by the Kafka comparison
+			continue
+		}
+
+		filters := make([][]byte, len(comp.verifiers))
+		commitHashes := make([][]byte, len(comp.verifiers))
+		for j, verifier := range comp.verifiers {
+			filters[j] = getMetadataBytes(verifier.Blocks[i], cb.BlockMetadataIndex_TRANSACTIONS_FILTER)
+			commitHashes[j] = getCommitHash(verifier.Blocks[i])
+		}
+
+		if deviating := deviatingFromMajority(filters); deviating != nil {
+			tIdx := firstDivergentTransaction(filters)
+			msg := fmt.Sprintf("Peers validated transaction %d of block %d differently (validation codes: %s)", tIdx, comp.verifiers[0].BlockNumbers[i], validationCodesAt(filters, tIdx))
+			return comp.blame(msg, deviating, comp.verifiers[0].BlockNumbers[i])
+		}
+
+		if deviating := deviatingFromMajority(commitHashes); deviating != nil {
+			msg := fmt.Sprintf("Peers computed different commit hashes for block %d", comp.verifiers[0].BlockNumbers[i])
+			return comp.blame(msg, deviating, comp.verifiers[0].BlockNumbers[i])
+		}
+	}
+
+	return nil
+}
+
+func (comp *PeerComparator) sameBlockData(i int) bool {
+	dataHash := comp.verifiers[0].Blocks[i].Header.DataHash
+	for _, verifier := range comp.verifiers[1:] {
+		if !bytes.Equal(verifier.Blocks[i].Header.DataHash, dataHash) {
+			return false
+		}
+	}
+	return true
+}
+
+func (comp *PeerComparator) blame(msg string, deviating []int, block uint64) []*verdicts.Verdict {
+	result := make([]*verdicts.Verdict, 0, len(deviating))
+	for _, j := range deviating {
+		verdict := verdicts.CreateVerdict(msg, comp.verifiers[j].Identity, 2)
+		// the blocks of all peers are needed to show the majority
+		for _, verifier := range comp.verifiers {
+			verdict.WithEvidence(verifier.Identity, block)
+		}
+		result = append(result, verdict)
+	}
+	return result
+}
+
+// deviatingFromMajority returns the indices of the values which differ from the value of the strict majority.
+// If there is no strict majority but the values differ, all indices are returned
+func deviatingFromMajority(values [][]byte) []int {
+	counts := make(map[string]int)
+	for _, value := range values {
+		counts[string(value)]++
+	}
+	if len(counts) <= 1 {
+		return nil
+	}
+
+	majority := ""
+	hasMajority := false
+	for value, count := range counts {
+		if 2*count > len(values) {
+			majority = value
+			hasMajority = true
+		}
+	}
+
+	deviating := make([]int, 0)
+	for j, value := range values {
+		if !hasMajority || string(value) != majority {
+			deviating = append(deviating, j)
+		}
+	}
+	return deviating
+}
+
+func firstDivergentTransaction(filters [][]byte) int {
+	for tIdx := 0; ; tIdx++ {
+		for _, filter := range filters[1:] {
+			if tIdx >= len(filter) || tIdx >= len(filters[0]) || filter[tIdx] != filters[0][tIdx] {
+				return tIdx
+			}
+		}
+	}
+}
+
+func validationCodesAt(filters [][]byte, tIdx int) string {
+	codes := ""
+	for j, filter := range filters {
+		if j > 0 {
+			codes += ", "
+		}
+		if tIdx < len(filter) {
+			codes += fmt.Sprintf("%d", filter[tIdx])
+		} else {
+			codes += "missing"
+		}
+	}
+	return codes
+}
+
+func getMetadataBytes(block *cb.Block, index cb.BlockMetadataIndex) []byte {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(index) {
+		return nil
+	}
+	return block.Metadata.Metadata[index]
+}
+
+// getCommitHash retrieves the commit hash, which the peer stores as marshaled Metadata in the COMMIT_HASH index
+func getCommitHash(block *cb.Block) []byte {
+	metadataBytes := getMetadataBytes(block, cb.BlockMetadataIndex_COMMIT_HASH)
+	if metadataBytes == nil {
+		return nil
+	}
+	metadata := &cb.Metadata{}
+	err := proto.Unmarshal(metadataBytes, metadata)
+	if err != nil {
+		return metadataBytes
+	}
+	return metadata.Value
+}
@@ -0,0 +1,71 @@
+package comparator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/comparator"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// committed returns the ledger of a peer, which wrote the given validation flags and commit hash into the last block
+func committed(t *testing.T, f *ledgertest.KafkaFixture, identity string, blocks []*cb.Block, filter []byte, commitHash string) *validator.Verifier {
+	last := proto.Clone(blocks[len(blocks)-1]).(*cb.Block)
+	commitHashMetadata, err := proto.Marshal(&cb.Metadata{Value: []byte(commitHash)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = filter
+	last.Metadata.Metadata = append(last.Metadata.Metadata, commitHashMetadata)
+	return newKafkaVerifier(t, f, identity, append(blocks[:len(blocks)-1:len(blocks)-1], last))
+}
+
+func TestComparePeerMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+
+	verdict := comparator.NewPeerComparator(
+		committed(t, f, "peerA", blocks, []byte{0, 0}, "hash"),
+		committed(t, f, "peerB", blocks, []byte{0, 0}, "hash"),
+	).ComparePeerMetadata()
+	if len(verdict) != 0 {
+		t.Fatalf("Expected the peers committing the block equally to pass, got: %s", verdict[0].Message())
+	}
+
+	// peerC deviates from the majority in the validation of the second transaction
+	verdict = comparator.NewPeerComparator(
+		committed(t, f, "peerA", blocks, []byte{0, 0}, "hash"),
+		committed(t, f, "peerB", blocks, []byte{0, 0}, "hash"),
+		committed(t, f, "peerC", blocks, []byte{0, 1}, "hash"),
+	).ComparePeerMetadata()
+	if len(verdict) != 1 || verdict[0].Identity() != "peerC" || verdict[0].Message() != "Peers validated transaction 1 of block 1 differently (validation codes: 0, 0, 1)" {
+		t.Fatalf("Expected peerC to be blamed for its validation flags, got %v", verdict)
+	}
+}
+
+func TestComparePeerMetadataWithoutMajority(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+
+	verdict := comparator.NewPeerComparator(
+		committed(t, f, "peerA", blocks, []byte{0, 0}, "hash"),
+		committed(t, f, "peerB", blocks, []byte{0, 0}, "other"),
+	).ComparePeerMetadata()
+	if len(verdict) != 2 || verdict[0].Message() != "Peers computed different commit hashes for block 1" {
+		t.Fatalf("Expected both peers to be blamed for their commit hashes, got %v", verdict)
+	}
+}
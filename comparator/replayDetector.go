@@ -0,0 +1,138 @@
+package comparator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// envelopeOccurrence is a single ordered envelope of one ledger. The envelope is nil, if it was verified before the checkpoint the ledger was resumed from
+type envelopeOccurrence struct {
+	verifier *validator.Verifier
+	envelope *cb.Envelope
+	block    uint64
+	offset   int64
+	hash     string
+	txID     string
+}
+
+// ReplayDetector indexes the tx_id and hash of every ordered envelope across all blocks and peers
+type ReplayDetector struct {
+	byHash map[string][]*envelopeOccurrence
+	byTxID map[string][]*envelopeOccurrence
+}
+
+// NewReplayDetector creates the index for the ledgers of the given verifiers
+func NewReplayDetector(verifiers ...*validator.Verifier) *ReplayDetector {
+	detector := &ReplayDetector{
+		byHash: make(map[string][]*envelopeOccurrence),
+		byTxID: make(map[string][]*envelopeOccurrence),
+	}
+
+	for _, verifier := range verifiers {
+		for _, record := range verifier.ResumedEnvelopes {
+			detector.add(&envelopeOccurrence{verifier: verifier, block: record.Block, offset: record.Offset, hash: record.Hash, txID: record.TxID})
+		}
+		for i, blockEnv := range verifier.Envelopes {
+			for _, env := range blockEnv {
+				if env.KafkaPayload == nil {
+					continue
+				}
+				occurrence := &envelopeOccurrence{
+					verifier: verifier,
+					envelope: env,
+					block:    verifier.BlockNumbers[i],
+					offset:   env.KafkaPayload.KafkaOffset,
+					hash:     hex.EncodeToString(computeHashOfEnvelope(env)),
+				}
+				channelHeader, err := validator.GetChannelHeaderFromEnvelope(env)
+				if err == nil {
+					occurrence.txID = channelHeader.TxId
+				}
+
+				detector.add(occurrence)
+			}
+		}
+	}
+
+	return detector
+}
+
+func (d *ReplayDetector) add(occurrence *envelopeOccurrence) {
+	d.byHash[occurrence.hash] = append(d.byHash[occurrence.hash], occurrence)
+	if occurrence.txID != "" {
+		d.byTxID[occurrence.txID] = append(d.byTxID[occurrence.txID], occurrence)
+	}
+}
+
+// DetectReplays reports envelopes which were ordered at multiple offsets and tx_ids which were used for different payloads.
+// If both copies carry a valid Kafka signature, the Kafka Cluster is blamed, otherwise the orderer which forwarded the unsigned copy
+func (d *ReplayDetector) DetectReplays() []*verdicts.Verdict {
+	var result []*verdicts.Verdict
+
+	for _, hash := range sortedKeys(d.byHash) {
+		first, second := firstConflict(d.byHash[hash], func(a *envelopeOccurrence, b *envelopeOccurrence) bool {
+			return a.offset != b.offset
+		})
+		if first == nil {
+			continue
+		}
+		msg := fmt.Sprintf("Envelope %s was ordered twice, at offset %d (block %d of %s) and at offset %d (block %d of %s)", hash, first.offset, first.block, first.verifier.Identity, second.offset, second.block, second.verifier.Identity)
+		result = append(result, attributeReplay(msg, first, second)...)
+	}
+
+	for _, txID := range sortedKeys(d.byTxID) {
+		first, second := firstConflict(d.byTxID[txID], func(a *envelopeOccurrence, b *envelopeOccurrence) bool {
+			return a.hash != b.hash
+		})
+		if first == nil {
+			continue
+		}
+		msg := fmt.Sprintf("tx_id %s was ordered with different payloads, at offset %d (block %d of %s) and at offset %d (block %d of %s)", txID, first.offset, first.block, first.verifier.Identity, second.offset, second.block, second.verifier.Identity)
+		result = append(result, attributeReplay(msg, first, second)...)
+	}
+
+	return result
+}
+
+// attributeReplay blames the Kafka Cluster, if it signed both copies, and otherwise the orderers which forwarded a copy without a valid Kafka signature.
+// The envelopes before the checkpoint were verified by the previous run, thus they carry a valid Kafka signature
+func attributeReplay(msg string, first *envelopeOccurrence, second *envelopeOccurrence) []*verdicts.Verdict {
+	var result []*verdicts.Verdict
+	for _, occurrence := range []*envelopeOccurrence{first, second} {
+		if occurrence.envelope != nil && !occurrence.verifier.IsSignedByKafka(occurrence.envelope) {
+			result = append(result, verdicts.CreateVerdict(msg+" without a valid Kafka signature", occurrence.verifier.Identity, 1))
+		}
+	}
+	if result == nil {
+		result = append(result, verdicts.CreateVerdict(msg, "Kafka Cluster", 0))
+	}
+	for _, v := range result {
+		v.WithEvidence(first.verifier.Identity, first.block).WithEvidence(second.verifier.Identity, second.block)
+	}
+	return result
+}
+
+func firstConflict(occurrences []*envelopeOccurrence, conflicts func(a *envelopeOccurrence, b *envelopeOccurrence) bool) (*envelopeOccurrence, *envelopeOccurrence) {
+	for i := 0; i < len(occurrences); i++ {
+		for j := i + 1; j < len(occurrences); j++ {
+			if conflicts(occurrences[i], occurrences[j]) {
+				return occurrences[i], occurrences[j]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func sortedKeys(index map[string][]*envelopeOccurrence) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
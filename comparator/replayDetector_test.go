@@ -0,0 +1,77 @@
+package comparator_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/comparator"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func newKafkaVerifier(t *testing.T, f *ledgertest.KafkaFixture, identity string, blocks []*cb.Block) *validator.Verifier {
+	v, err := validator.NewVerifier(blocks, f.KafkaPublicKey, identity, ledgertest.MaxBatchSize, 50000, 100000, validator.KafkaProofMessageSizeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// firstEnvelope returns the first envelope of the block
+func firstEnvelope(t *testing.T, block *cb.Block) *cb.Envelope {
+	env := &cb.Envelope{}
+	err := proto.Unmarshal(block.Data.Data[0], env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestDetectReplays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+
+	if verdict := comparator.NewReplayDetector(newKafkaVerifier(t, f, "peerA", blocks), newKafkaVerifier(t, f, "peerB", blocks)).DetectReplays(); len(verdict) != 0 {
+		t.Fatalf("Expected the same envelopes at the same offsets of both peers to pass, got: %s", verdict[0].Message())
+	}
+
+	// Kafka signed the envelope of offset 0 again at offset 4
+	replayed := append(blocks[:3:3], f.Block(blocks[2], []ledgertest.Message{{Offset: 4, Replay: firstEnvelope(t, blocks[1])}, {Offset: 5}}, 0, 0))
+	verdict := comparator.NewReplayDetector(newKafkaVerifier(t, f, "peerA", replayed)).DetectReplays()
+	if len(verdict) != 1 || verdict[0].Identity() != "Kafka Cluster" || !strings.HasSuffix(verdict[0].Message(), "was ordered twice, at offset 0 (block 1 of peerA) and at offset 4 (block 3 of peerA)") {
+		t.Fatalf("Expected the replay signed by Kafka to be blamed on the Kafka Cluster, got %v", verdict)
+	}
+}
+
+func TestDetectReplayWithoutKafkaSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+
+	// the orderer of peerB copies the envelope of offset 0 into its block without a valid Kafka signature
+	copied := f.Block(blocks[1], []ledgertest.Message{{Offset: 2, Replay: firstEnvelope(t, blocks[1])}, {Offset: 3}}, 0, 0)
+	env := firstEnvelope(t, copied)
+	env.KafkaPayload.KafkaSignatureHeader = make([]byte, len(env.KafkaPayload.KafkaSignatureHeader))
+	copied.Data.Data[0], err = proto.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verdict := comparator.NewReplayDetector(newKafkaVerifier(t, f, "peerA", blocks), newKafkaVerifier(t, f, "peerB", append(blocks[:2:2], copied))).DetectReplays()
+	if len(verdict) != 1 || verdict[0].Identity() != "peerB" || !strings.HasSuffix(verdict[0].Message(), "without a valid Kafka signature") {
+		t.Fatalf("Expected the replay without Kafka signature to be blamed on the orderer of peerB, got %v", verdict)
+	}
+}
@@ -0,0 +1,138 @@
+package evidence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+// FormatVersion is the version of the bundle format. It is increased on every change, which older readers cannot handle
+const FormatVersion = 2
+
+// ManifestFile is the name of the manifest within a bundle
+const ManifestFile = "manifest.json"
+
+// Manifest describes an evidence bundle, which contains the minimal set of blocks to reproduce a single verdict
+type Manifest struct {
+	FormatVersion int            `json:"format_version"`
+	CreatedAt     string         `json:"created_at"`
+	Verdict       *VerdictInfo   `json:"verdict"`
+	Check         string         `json:"check"`
+	ConsensusType string         `json:"consensus_type"`
+	ChannelName   string         `json:"channel_name"`
+	Parameters    *Parameters    `json:"parameters"`
+	KeyIDs        []string       `json:"key_ids"`
+	Ledgers       []*LedgerEntry `json:"ledgers"`
+	// Redacted lists the envelopes, whose payload data was removed. Bundles of version 1 never contain redacted envelopes
+	Redacted []*RedactedEnvelope `json:"redacted,omitempty"`
+}
+
+// VerdictInfo contains the verdict, which the bundle proves
+type VerdictInfo struct {
+	Message  string `json:"message"`
+	Identity string `json:"identity"`
+	Type     int    `json:"type"`
+	Category string `json:"category,omitempty"`
+}
+
+// Parameters contains the parameters of the judge run, which are not part of the ledgers
+type Parameters struct {
+	MaxBatchSize      int    `json:"max_batch_size"`
+	PreferredMaxBytes int    `json:"preferred_max_bytes"`
+	AbsoluteMaxBytes  int    `json:"absolute_max_bytes"`
+	OrdererVersion    string `json:"orderer_version"`
+	BatchTimeout      string `json:"batch_timeout,omitempty"`
+	TimingTolerance   string `json:"timing_tolerance,omitempty"`
+}
+
+// LedgerEntry lists the blocks of a single peer, which are contained in the bundle
+type LedgerEntry struct {
+	Identity string       `json:"identity"`
+	Blocks   []*FileEntry `json:"blocks"`
+}
+
+// FileEntry is a single block of the bundle together with its SHA-256 digest
+type FileEntry struct {
+	Number uint64 `json:"number"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Bundle is an evidence bundle, whose blocks were verified against the digests of its manifest
+type Bundle struct {
+	Manifest *Manifest
+	Ledgers  []*backend.Ledger
+}
+
+// ReadBundle reads the bundle in the given directory. Every block must match the SHA-256 digest and the number recorded in the manifest
+func ReadBundle(dir string) (*Bundle, error) {
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the manifest of the bundle: %s", err)
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the manifest of the bundle: %s", err)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("Bundle has format version %d, but only versions up to %d are supported", manifest.FormatVersion, FormatVersion)
+	}
+	if manifest.Verdict == nil || manifest.Parameters == nil {
+		return nil, fmt.Errorf("Manifest of the bundle does not contain a verdict and its parameters")
+	}
+
+	bundle := &Bundle{Manifest: manifest, Ledgers: make([]*backend.Ledger, 0, len(manifest.Ledgers))}
+	for _, entry := range manifest.Ledgers {
+		ledger := &backend.Ledger{Identity: entry.Identity, Blocks: make([]*cb.Block, 0, len(entry.Blocks))}
+		for _, file := range entry.Blocks {
+			// the paths are relative to the bundle and must not point outside of it
+			blockPath := path.Clean(file.Path)
+			if path.IsAbs(blockPath) || blockPath == ".." || strings.HasPrefix(blockPath, "../") {
+				return nil, fmt.Errorf("Block %d of %s is stored outside of the bundle", file.Number, entry.Identity)
+			}
+
+			blockBytes, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(blockPath)))
+			if err != nil {
+				return nil, fmt.Errorf("Unable to read block %d of %s: %s", file.Number, entry.Identity, err)
+			}
+			if digest(blockBytes) != file.SHA256 {
+				return nil, fmt.Errorf("Block %d of %s does not match its SHA-256 digest", file.Number, entry.Identity)
+			}
+
+			block := &cb.Block{}
+			err = proto.Unmarshal(blockBytes, block)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to parse block %d of %s: %s", file.Number, entry.Identity, err)
+			}
+			if block.Header == nil || block.Header.Number != file.Number {
+				return nil, fmt.Errorf("Block %d of %s has a different number than recorded in the manifest", file.Number, entry.Identity)
+			}
+			if len(ledger.Blocks) > 0 && ledger.Blocks[len(ledger.Blocks)-1].Header.Number >= file.Number {
+				return nil, fmt.Errorf("Blocks of %s are not sorted by their number", entry.Identity)
+			}
+			ledger.Blocks = append(ledger.Blocks, block)
+		}
+		if len(ledger.Blocks) == 0 || ledger.Blocks[0].Header.Number != 0 {
+			return nil, fmt.Errorf("Bundle does not contain the genesis block of %s", entry.Identity)
+		}
+		bundle.Ledgers = append(bundle.Ledgers, ledger)
+	}
+
+	return bundle, nil
+}
+
+func digest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,228 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// CertificateFormatVersion is the version of the certificate format
+const CertificateFormatVersion = 1
+
+// Certificate is the report of a single judge run, which is signed by the judge
+type Certificate struct {
+	FormatVersion int                 `json:"format_version"`
+	JudgeVersion  string              `json:"judge_version"`
+	CreatedAt     string              `json:"created_at"`
+	ConsensusType string              `json:"consensus_type"`
+	ChannelName   string              `json:"channel_name"`
+	Verdicts      []*CertifiedVerdict `json:"verdicts"`
+	Ledgers       []*LedgerDigest     `json:"ledgers"`
+	KeyIDs        []string            `json:"key_ids"`
+}
+
+// CertifiedVerdict is a single verdict of the certificate together with the check rendering it and the blocks proving it
+type CertifiedVerdict struct {
+	Check    string           `json:"check"`
+	Message  string           `json:"message"`
+	Identity string           `json:"identity"`
+	Type     int              `json:"type"`
+	Category string           `json:"category,omitempty"`
+	Evidence []*EvidenceEntry `json:"evidence,omitempty"`
+}
+
+// EvidenceEntry references the blocks of a single ledger
+type EvidenceEntry struct {
+	Ledger string   `json:"ledger"`
+	Blocks []uint64 `json:"blocks"`
+}
+
+// LedgerDigest identifies the ledger of a single peer, which was input to the judge
+type LedgerDigest struct {
+	Identity string `json:"identity"`
+	Height   int    `json:"height"`
+	// SHA256 is the digest of the concatenated block files of the ledger
+	SHA256 string `json:"sha256"`
+	// HeadHash is the header hash of the last block of the ledger
+	HeadHash string `json:"head_hash"`
+}
+
+// SignedCertificate contains the certificate together with the Ed25519 signature of the judge.
+// The signature covers the compact JSON encoding of the certificate
+type SignedCertificate struct {
+	Certificate json.RawMessage `json:"certificate"`
+	JudgeKeyID  string          `json:"judge_key_id"`
+	Signature   []byte          `json:"signature"`
+}
+
+// NewCertificate creates an empty certificate for a judge run on the given channel
+func NewCertificate(judgeVersion string, consensusType string, options *backend.Options) (*Certificate, error) {
+	certificate := &Certificate{
+		FormatVersion: CertificateFormatVersion,
+		JudgeVersion:  judgeVersion,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ConsensusType: consensusType,
+		ChannelName:   options.ChannelName,
+		Verdicts:      make([]*CertifiedVerdict, 0),
+		Ledgers:       make([]*LedgerDigest, 0),
+		KeyIDs:        make([]string, 0),
+	}
+
+	if consensusType == "kafka" {
+		keyBytes, err := ioutil.ReadFile(options.KafkaPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the Kafka public key: %s", err)
+		}
+		certificate.addKeyIDs(KafkaKeyID(keyBytes))
+	}
+
+	return certificate, nil
+}
+
+// AddLedger records the digest of an input ledger together with the key ids of all orderers, which signed its blocks
+func (c *Certificate) AddLedger(ledger *backend.Ledger, sha256 string) {
+	digest := &LedgerDigest{Identity: ledger.Identity, Height: len(ledger.Blocks), SHA256: sha256}
+	if len(ledger.Blocks) > 0 {
+		digest.HeadHash = fmt.Sprintf("%x", validator.BlockHeaderHash(ledger.Blocks[len(ledger.Blocks)-1].Header))
+	}
+	c.Ledgers = append(c.Ledgers, digest)
+
+	for _, block := range ledger.Blocks {
+		c.addKeyIDs(signerKeyIDs(block)...)
+	}
+}
+
+// AddVerdicts records the verdicts, which were rendered by the given check
+func (c *Certificate) AddVerdicts(check string, verdict []*verdicts.Verdict) {
+	for _, v := range verdict {
+		c.Verdicts = append(c.Verdicts, NewCertifiedVerdict(check, v))
+	}
+}
+
+// NewCertifiedVerdict converts the verdict, which was rendered by the given check, into its serializable form
+func NewCertifiedVerdict(check string, v *verdicts.Verdict) *CertifiedVerdict {
+	certified := &CertifiedVerdict{
+		Check:    check,
+		Message:  v.Message(),
+		Identity: v.Identity(),
+		Type:     int(v.Type()),
+		Category: v.Category(),
+	}
+	for _, ev := range v.Evidence() {
+		certified.Evidence = append(certified.Evidence, &EvidenceEntry{Ledger: ev.Ledger, Blocks: ev.Blocks})
+	}
+	return certified
+}
+
+func (c *Certificate) addKeyIDs(keyIDs ...string) {
+	for _, keyID := range keyIDs {
+		index := sort.SearchStrings(c.KeyIDs, keyID)
+		if index < len(c.KeyIDs) && c.KeyIDs[index] == keyID {
+			continue
+		}
+		c.KeyIDs = append(c.KeyIDs, "")
+		copy(c.KeyIDs[index+1:], c.KeyIDs[index:])
+		c.KeyIDs[index] = keyID
+	}
+}
+
+// JudgeKeyID identifies the Ed25519 public key of a judge by its SHA-256 digest
+func JudgeKeyID(publicKey []byte) string {
+	return "judge:sha256:" + digest(publicKey)
+}
+
+// Sign signs the certificate with the Ed25519 secret key of the judge. Like the Kafka key pair, the key file contains the raw
+// secret key, i.e. the 64 bytes of seed and public key, or only the 32 bytes of the seed
+func (c *Certificate) Sign(secretKeyPath string) (*SignedCertificate, error) {
+	keyBytes, err := ioutil.ReadFile(secretKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the secret key of the judge: %s", err)
+	}
+
+	var secretKey ed25519.PrivateKey
+	switch len(keyBytes) {
+	case ed25519.PrivateKeySize:
+		secretKey = ed25519.PrivateKey(keyBytes)
+	case ed25519.SeedSize:
+		secretKey = ed25519.NewKeyFromSeed(keyBytes)
+	default:
+		return nil, fmt.Errorf("Secret key of the judge has %d bytes, but an Ed25519 key has %d bytes", len(keyBytes), ed25519.PrivateKeySize)
+	}
+
+	certificateBytes, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedCertificate{
+		Certificate: certificateBytes,
+		JudgeKeyID:  JudgeKeyID(secretKey.Public().(ed25519.PublicKey)),
+		Signature:   ed25519.Sign(secretKey, certificateBytes),
+	}, nil
+}
+
+// Write stores the signed certificate as JSON file
+func (s *SignedCertificate) Write(path string) error {
+	signedBytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, signedBytes, 0644)
+}
+
+// ReadCertificate reads a signed certificate from the given JSON file
+func ReadCertificate(path string) (*SignedCertificate, error) {
+	signedBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the certificate: %s", err)
+	}
+	signed := &SignedCertificate{}
+	err = json.Unmarshal(signedBytes, signed)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the certificate: %s", err)
+	}
+	return signed, nil
+}
+
+// Verify checks the signature of the certificate with the trusted Ed25519 public key of the judge, which is read from the given raw key file.
+// Only if the signature is valid, the certificate is returned
+func (s *SignedCertificate) Verify(publicKeyPath string) (*Certificate, error) {
+	publicKey, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the public key of the judge: %s", err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Public key of the judge has %d bytes, but an Ed25519 key has %d bytes", len(publicKey), ed25519.PublicKeySize)
+	}
+	if s.JudgeKeyID != JudgeKeyID(publicKey) {
+		return nil, fmt.Errorf("Certificate was signed by judge %s, but the given public key is %s", s.JudgeKeyID, JudgeKeyID(publicKey))
+	}
+
+	// the certificate may have been re-indented, thus the signature is verified on its compact encoding
+	var certificateBytes bytes.Buffer
+	err = json.Compact(&certificateBytes, s.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the certificate: %s", err)
+	}
+	if !ed25519.Verify(publicKey, certificateBytes.Bytes(), s.Signature) {
+		return nil, fmt.Errorf("Signature of the certificate is invalid")
+	}
+
+	certificate := &Certificate{}
+	err = json.Unmarshal(certificateBytes.Bytes(), certificate)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the certificate: %s", err)
+	}
+	if certificate.FormatVersion != CertificateFormatVersion {
+		return nil, fmt.Errorf("Certificate has format version %d, but only version %d is supported", certificate.FormatVersion, CertificateFormatVersion)
+	}
+	return certificate, nil
+}
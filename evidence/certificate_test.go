@@ -0,0 +1,107 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+// judgeKeys writes a new Ed25519 key pair of a judge into the directory and returns the paths of the secret and the public key
+func judgeKeys(t *testing.T, dir string, name string) (string, string) {
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretKeyPath, publicKeyPath := filepath.Join(dir, name+".sk"), filepath.Join(dir, name+".pk")
+	if err := ioutil.WriteFile(secretKeyPath, secretKey.Seed(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(publicKeyPath, publicKey, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return secretKeyPath, publicKeyPath
+}
+
+// signedCertificate certifies the verdicts of the judge on equivocating ledgers and writes the signed certificate into the directory
+func signedCertificate(t *testing.T, dir string, f *ledgertest.KafkaFixture, secretKeyPath string) string {
+	ledgers := equivocatingLedgers(f)
+	check, verdict := judge(t, f.Options(), ledgers)
+	certificate, err := NewCertificate("test", "kafka", f.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ledger := range ledgers {
+		certificate.AddLedger(ledger, "")
+	}
+	certificate.AddVerdicts(check, verdict)
+
+	signed, err := certificate.Sign(secretKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "certificate.json")
+	if err := signed.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCertificateSignAndVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	secretKeyPath, publicKeyPath := judgeKeys(t, dir, "judge")
+
+	signed, err := ReadCertificate(signedCertificate(t, dir, f, secretKeyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := signed.Verify(publicKeyPath)
+	if err != nil {
+		t.Fatalf("Expected the certificate to be valid, got: %s", err)
+	}
+	if len(certificate.Ledgers) != 2 || len(certificate.Verdicts) == 0 || certificate.Verdicts[0].Identity != "Kafka Cluster" {
+		t.Fatalf("Expected the verdicts against Kafka on both ledgers to be certified, got %d ledgers and %d verdicts", len(certificate.Ledgers), len(certificate.Verdicts))
+	}
+	// the blocks of the fixture carry no orderer signatures, thus only the Kafka key is named
+	if len(certificate.KeyIDs) != 1 || !strings.HasPrefix(certificate.KeyIDs[0], "kafka:sha256:") {
+		t.Fatalf("Expected the key id of Kafka, got %v", certificate.KeyIDs)
+	}
+}
+
+func TestCertificateVerifyRejectsTamperedCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	secretKeyPath, publicKeyPath := judgeKeys(t, dir, "judge")
+
+	signed, err := ReadCertificate(signedCertificate(t, dir, f, secretKeyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed.Certificate = bytes.Replace(signed.Certificate, []byte("Kafka Cluster"), []byte("peerA"), 1)
+	_, err = signed.Verify(publicKeyPath)
+	if err == nil || err.Error() != "Signature of the certificate is invalid" {
+		t.Fatalf("Expected the certificate blaming another identity to be rejected, got: %v", err)
+	}
+
+	// a certificate signed by another judge is not accepted with the trusted key
+	_, foreignPublicKeyPath := judgeKeys(t, dir, "foreign")
+	_, err = signed.Verify(foreignPublicKeyPath)
+	if err == nil || !strings.HasPrefix(err.Error(), "Certificate was signed by judge") {
+		t.Fatalf("Expected the certificate of another judge to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package evidence
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// Exporter writes one evidence bundle per verdict into a directory
+type Exporter struct {
+	dir           string
+	consensusType string
+	options       *backend.Options
+	ledgers       []*backend.Ledger
+	kafkaKeyID    string
+	redact        bool
+	bundles       int
+}
+
+// NewExporter creates an Exporter writing into the given directory, which is created if necessary.
+// If redact is set, the payload data of the envelopes is removed from all bundles, whose verdict does not depend on it
+func NewExporter(dir string, consensusType string, options *backend.Options, ledgers []*backend.Ledger, redact bool) (*Exporter, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := &Exporter{
+		dir:           dir,
+		consensusType: consensusType,
+		options:       options,
+		ledgers:       ledgers,
+		redact:        redact,
+	}
+
+	if consensusType == "kafka" {
+		keyBytes, err := ioutil.ReadFile(options.KafkaPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the Kafka public key: %s", err)
+		}
+		exporter.kafkaKeyID = KafkaKeyID(keyBytes)
+	}
+
+	return exporter, nil
+}
+
+// KafkaKeyID identifies the public key of the Kafka Cluster by its SHA-256 digest
+func KafkaKeyID(keyBytes []byte) string {
+	return "kafka:sha256:" + digest(keyBytes)
+}
+
+// CertificateKeyID identifies an orderer by the SHA-256 digest of its DER encoded certificate
+func CertificateKeyID(der []byte) string {
+	return "x509:sha256:" + digest(der)
+}
+
+// Export writes the bundle of the verdict, which was rendered by the given check, and returns its directory.
+// The bundle contains the blocks referenced by the verdict, together with the genesis block and the config blocks in effect,
+// which are needed to verify the signatures. If the verdict does not reference any blocks, the complete ledgers are exported.
+// The manifest lists every envelope, whose payload data was redacted
+func (e *Exporter) Export(check string, verdict *verdicts.Verdict) (string, error) {
+	e.bundles++
+	bundleDir := filepath.Join(e.dir, fmt.Sprintf("verdict-%03d", e.bundles))
+
+	selection := e.selectBlocks(verdict)
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		Verdict: &VerdictInfo{
+			Message:  verdict.Message(),
+			Identity: verdict.Identity(),
+			Type:     int(verdict.Type()),
+			Category: verdict.Category(),
+		},
+		Check:         check,
+		ConsensusType: e.consensusType,
+		ChannelName:   e.options.ChannelName,
+		Parameters: &Parameters{
+			MaxBatchSize:      e.options.MaxBatchSize,
+			PreferredMaxBytes: e.options.PreferredMaxBytes,
+			AbsoluteMaxBytes:  e.options.AbsoluteMaxBytes,
+			OrdererVersion:    e.options.OrdererVersion,
+		},
+		KeyIDs:  make([]string, 0),
+		Ledgers: make([]*LedgerEntry, 0),
+	}
+	if e.options.BatchTimeout > 0 {
+		manifest.Parameters.BatchTimeout = e.options.BatchTimeout.String()
+		manifest.Parameters.TimingTolerance = e.options.TimingTolerance.String()
+	}
+	if e.kafkaKeyID != "" {
+		manifest.KeyIDs = append(manifest.KeyIDs, e.kafkaKeyID)
+	}
+	keyIDs := make(map[string]bool)
+
+	for k, ledger := range e.ledgers {
+		blocks := selection[k]
+		if len(blocks) == 0 {
+			continue
+		}
+
+		ledgerDir := fmt.Sprintf("ledger-%d", k)
+		err := os.MkdirAll(filepath.Join(bundleDir, ledgerDir), 0755)
+		if err != nil {
+			return "", err
+		}
+
+		entry := &LedgerEntry{Identity: ledger.Identity, Blocks: make([]*FileEntry, 0, len(blocks))}
+		for _, block := range blocks {
+			if e.redact && redactableChecks[check] {
+				var redacted []*RedactedEnvelope
+				block, redacted, err = redactBlock(block, ledger.Identity)
+				if err != nil {
+					return "", err
+				}
+				manifest.Redacted = append(manifest.Redacted, redacted...)
+			}
+
+			blockBytes, err := proto.Marshal(block)
+			if err != nil {
+				return "", err
+			}
+			blockPath := path.Join(ledgerDir, fmt.Sprintf("block_%d.block", block.Header.Number))
+			err = ioutil.WriteFile(filepath.Join(bundleDir, filepath.FromSlash(blockPath)), blockBytes, 0644)
+			if err != nil {
+				return "", err
+			}
+			entry.Blocks = append(entry.Blocks, &FileEntry{Number: block.Header.Number, Path: blockPath, SHA256: digest(blockBytes)})
+
+			for _, keyID := range signerKeyIDs(block) {
+				keyIDs[keyID] = true
+			}
+		}
+		manifest.Ledgers = append(manifest.Ledgers, entry)
+	}
+
+	sortedKeyIDs := make([]string, 0, len(keyIDs))
+	for keyID := range keyIDs {
+		sortedKeyIDs = append(sortedKeyIDs, keyID)
+	}
+	sort.Strings(sortedKeyIDs)
+	manifest.KeyIDs = append(manifest.KeyIDs, sortedKeyIDs...)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(filepath.Join(bundleDir, ManifestFile), manifestBytes, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return bundleDir, nil
+}
+
+// selectBlocks returns the blocks of each ledger, which have to be exported for the verdict, sorted by their number
+func (e *Exporter) selectBlocks(verdict *verdicts.Verdict) [][]*cb.Block {
+	numbers := make([]map[uint64]bool, len(e.ledgers))
+	for k := range e.ledgers {
+		numbers[k] = make(map[uint64]bool)
+	}
+
+	evidence := verdict.Evidence()
+	if len(evidence) == 0 {
+		for k, ledger := range e.ledgers {
+			for _, block := range ledger.Blocks {
+				numbers[k][block.Header.Number] = true
+			}
+		}
+	}
+	for _, ev := range evidence {
+		for k, ledger := range e.ledgers {
+			if ledger.Identity != ev.Ledger {
+				continue
+			}
+			for _, number := range ev.Blocks {
+				numbers[k][number] = true
+			}
+		}
+	}
+
+	selection := make([][]*cb.Block, len(e.ledgers))
+	for k, ledger := range e.ledgers {
+		if len(numbers[k]) == 0 {
+			continue
+		}
+
+		highest := uint64(0)
+		for number := range numbers[k] {
+			if number > highest {
+				highest = number
+			}
+		}
+
+		// the genesis block and the most recent config block before each referenced block contain the channel config in effect.
+		// The successor of the last referenced block shows, whether the peer continued to commit blocks after it
+		numbers[k][0] = true
+		lastConfig := uint64(0)
+		for _, block := range ledger.Blocks {
+			if numbers[k][block.Header.Number] {
+				numbers[k][lastConfig] = true
+			}
+			if block.Header.Number == highest+1 {
+				numbers[k][block.Header.Number] = true
+			}
+			if channelconfig.IsConfigBlock(block) {
+				lastConfig = block.Header.Number
+			}
+		}
+
+		for _, block := range ledger.Blocks {
+			if numbers[k][block.Header.Number] {
+				selection[k] = append(selection[k], block)
+			}
+		}
+	}
+
+	return selection
+}
+
+// signerKeyIDs returns the key ids of all identities, which signed the block in its SIGNATURES metadata
+func signerKeyIDs(block *cb.Block) []string {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_SIGNATURES) {
+		return nil
+	}
+	metadata := &cb.Metadata{}
+	if proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES], metadata) != nil {
+		return nil
+	}
+
+	keyIDs := make([]string, 0)
+	for _, metadataSignature := range metadata.Signatures {
+		signatureHeader := &cb.SignatureHeader{}
+		if proto.Unmarshal(metadataSignature.SignatureHeader, signatureHeader) != nil {
+			continue
+		}
+		serializedIdentity := &mb.SerializedIdentity{}
+		if proto.Unmarshal(signatureHeader.Creator, serializedIdentity) != nil {
+			continue
+		}
+		pemBlock, _ := pem.Decode(serializedIdentity.IdBytes)
+		if pemBlock == nil {
+			continue
+		}
+		keyIDs = append(keyIDs, CertificateKeyID(pemBlock.Bytes))
+	}
+	return keyIDs
+}
@@ -0,0 +1,108 @@
+package evidence
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// redactableChecks are the checks, whose verdicts only depend on inputs that stay authenticated once the payload data of the envelopes is removed:
+// the leaves and roots of the Merkle proofs, the consumer messages of TTC and connect messages and the block headers of the raft and BFT ledgers.
+// The Kafka offsets and channel headers of redacted envelopes are no longer bound to their leaf hash, thus all checks reading them need the complete envelopes
+var redactableChecks = map[string]bool{
+	backend.CheckTTCMessages:   true,
+	backend.CheckMerkleBatches: true,
+	backend.CheckForks:         true,
+	backend.CheckEquivocations: true,
+}
+
+// RedactedEnvelope is an envelope of the bundle, whose payload data was removed. Its header, signature and Kafka payload are kept
+type RedactedEnvelope struct {
+	Ledger string `json:"ledger"`
+	Block  uint64 `json:"block"`
+	Index  int    `json:"index"`
+	// SHA256 is the digest of the original envelope, as it is stored in the block data of the ledger
+	SHA256 string `json:"sha256"`
+	// KafkaLeafSHA256 is the digest of the data, which Kafka signed for the original envelope, i.e. its Merkle leaf
+	KafkaLeafSHA256 string `json:"kafka_leaf_sha256,omitempty"`
+}
+
+// redactBlock returns a copy of the block, in which the payload data of all envelopes is removed, together with the list of
+// redacted envelopes. The genesis block and config blocks are never redacted, since they contain the channel config
+func redactBlock(block *cb.Block, ledger string) (*cb.Block, []*RedactedEnvelope, error) {
+	if block.Header.Number == 0 || channelconfig.IsConfigBlock(block) {
+		return block, nil, nil
+	}
+
+	redactedBlock := proto.Clone(block).(*cb.Block)
+	redacted := make([]*RedactedEnvelope, 0, len(block.Data.Data))
+	for tIdx, data := range block.Data.Data {
+		env := &cb.Envelope{}
+		err := proto.Unmarshal(data, env)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to unmarshal envelope %d of block %d: %s", tIdx, block.Header.Number, err)
+		}
+		payload := &cb.Payload{}
+		err = proto.Unmarshal(env.Payload, payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to unmarshal the payload of envelope %d of block %d: %s", tIdx, block.Header.Number, err)
+		}
+
+		entry := &RedactedEnvelope{Ledger: ledger, Block: block.Header.Number, Index: tIdx, SHA256: digest(data)}
+		if env.KafkaPayload != nil {
+			entry.KafkaLeafSHA256 = digest(validator.GetKafkaSignedDataFromEnvelope(env))
+		}
+
+		env.Payload, err = proto.Marshal(&cb.Payload{Header: payload.Header})
+		if err != nil {
+			return nil, nil, err
+		}
+		redactedBlock.Data.Data[tIdx], err = proto.Marshal(env)
+		if err != nil {
+			return nil, nil, err
+		}
+		redacted = append(redacted, entry)
+	}
+
+	return redactedBlock, redacted, nil
+}
+
+// redactedBlocks returns the numbers of the blocks of the given ledger, which contain redacted envelopes
+func redactedBlocks(redacted []*RedactedEnvelope, ledger string) map[uint64]bool {
+	blocks := make(map[uint64]bool)
+	for _, entry := range redacted {
+		if entry.Ledger == ledger {
+			blocks[entry.Block] = true
+		}
+	}
+	return blocks
+}
+
+// redactedLeaves maps the redacted envelopes of a Kafka verifier to their leaf hashes recorded in the manifest
+func redactedLeaves(redacted []*RedactedEnvelope, v *validator.Verifier) error {
+	positions := make(map[uint64]int)
+	for i, number := range v.BlockNumbers {
+		positions[number] = i
+	}
+
+	for _, entry := range redacted {
+		if entry.Ledger != v.Identity || entry.KafkaLeafSHA256 == "" {
+			continue
+		}
+		i, ok := positions[entry.Block]
+		if !ok || entry.Index < 0 || entry.Index >= len(v.Envelopes[i]) {
+			return fmt.Errorf("Manifest lists the redacted envelope %d of block %d, which is not part of the bundle", entry.Index, entry.Block)
+		}
+		leafHash, err := hex.DecodeString(entry.KafkaLeafSHA256)
+		if err != nil {
+			return fmt.Errorf("Manifest lists an invalid leaf hash for envelope %d of block %d: %s", entry.Index, entry.Block, err)
+		}
+		v.RedactedLeaves[v.Envelopes[i][entry.Index]] = leafHash
+	}
+	return nil
+}
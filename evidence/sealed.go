@@ -0,0 +1,309 @@
+package evidence
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jamesruan/sodium"
+)
+
+// SealedFormatVersion is the version of the encrypted container format
+const SealedFormatVersion = 1
+
+// SealedExtension is appended to the path of every encrypted bundle or certificate
+const SealedExtension = ".sealed"
+
+const (
+	// ContentDirectory marks a container, whose plaintext is a tar archive of a directory, e.g. an evidence bundle
+	ContentDirectory = "directory"
+	// ContentFile marks a container, whose plaintext is a single file, e.g. a verdict certificate
+	ContentFile = "file"
+)
+
+// SealedHeader describes an encrypted container. It is not encrypted, but authenticated as additional data of the ciphertext,
+// thus every recipient can read it and detect if it was altered
+type SealedHeader struct {
+	FormatVersion int    `json:"format_version"`
+	CreatedAt     string `json:"created_at"`
+	Content       string `json:"content"`
+	Name          string `json:"name"`
+	// SHA256 is the digest of the plaintext, i.e. of the archived bundle or of the certificate
+	SHA256     string       `json:"sha256"`
+	Recipients []*Recipient `json:"recipients"`
+}
+
+// Recipient is a party, which can decrypt the container. The content key is put into a sealed box for its public key
+type Recipient struct {
+	KeyID     string `json:"key_id"`
+	SealedKey []byte `json:"sealed_key"`
+}
+
+// SealedContainer contains the plaintext encrypted with a fresh content key, using ChaCha20-Poly1305 with the compact
+// JSON encoding of the header as additional data
+type SealedContainer struct {
+	Header     json.RawMessage `json:"header"`
+	Nonce      []byte          `json:"nonce"`
+	Ciphertext []byte          `json:"ciphertext"`
+}
+
+// RecipientKeyID identifies the Curve25519 box public key of a recipient by its SHA-256 digest
+func RecipientKeyID(publicKey []byte) string {
+	return "box:sha256:" + digest(publicKey)
+}
+
+// Seal encrypts the bundle directory or file at the given path to the box public keys in the given raw key files.
+// The container is written next to the path with the SealedExtension and the plaintext is removed
+func Seal(path string, recipientKeys []string) (string, error) {
+	sealedPath := strings.TrimSuffix(path, string(filepath.Separator)) + SealedExtension
+	container, err := Encrypt(path, recipientKeys)
+	if err != nil {
+		return "", err
+	}
+	err = container.Write(sealedPath)
+	if err != nil {
+		return "", err
+	}
+	return sealedPath, os.RemoveAll(path)
+}
+
+// Encrypt encrypts the directory or file at the given path to the box public keys in the given raw key files
+func Encrypt(path string, recipientKeys []string) (*SealedContainer, error) {
+	if len(recipientKeys) == 0 {
+		return nil, fmt.Errorf("At least one recipient is needed to encrypt %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var plaintext []byte
+	header := &SealedHeader{
+		FormatVersion: SealedFormatVersion,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		Name:          info.Name(),
+		Recipients:    make([]*Recipient, 0, len(recipientKeys)),
+	}
+	if info.IsDir() {
+		header.Content = ContentDirectory
+		plaintext, err = ArchiveDir(path)
+	} else {
+		header.Content = ContentFile
+		plaintext, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	header.SHA256 = digest(plaintext)
+
+	key := sodium.AEADCPKey{Bytes: make([]byte, sodium.AEADCPKey{}.Size())}
+	nonce := sodium.AEADCPNonce{Bytes: make([]byte, sodium.AEADCPNonce{}.Size())}
+	_, err = rand.Read(key.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	_, err = rand.Read(nonce.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recipientKey := range recipientKeys {
+		publicKey, err := ioutil.ReadFile(recipientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the public key of recipient %s: %s", recipientKey, err)
+		}
+		if len(publicKey) != (sodium.BoxPublicKey{}).Size() {
+			return nil, fmt.Errorf("Public key of recipient %s has %d bytes, but a box public key has %d bytes", recipientKey, len(publicKey), sodium.BoxPublicKey{}.Size())
+		}
+		header.Recipients = append(header.Recipients, &Recipient{
+			KeyID:     RecipientKeyID(publicKey),
+			SealedKey: key.Bytes.SealedBox(sodium.BoxPublicKey{Bytes: publicKey}),
+		})
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedContainer{
+		Header:     headerBytes,
+		Nonce:      nonce.Bytes,
+		Ciphertext: sodium.Bytes(plaintext).AEADCPEncrypt(sodium.Bytes(headerBytes), nonce, key),
+	}, nil
+}
+
+// Write stores the encrypted container as JSON file
+func (c *SealedContainer) Write(path string) error {
+	containerBytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, containerBytes, 0644)
+}
+
+// ReadSealedContainer reads an encrypted container from the given JSON file
+func ReadSealedContainer(path string) (*SealedContainer, error) {
+	containerBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the encrypted container: %s", err)
+	}
+	container := &SealedContainer{}
+	err = json.Unmarshal(containerBytes, container)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the encrypted container: %s", err)
+	}
+	return container, nil
+}
+
+// Decrypt opens the container with the raw box secret key in the given file. The plaintext is only returned together with
+// its header, if the header is authentic and the plaintext matches the digest of the header
+func (c *SealedContainer) Decrypt(secretKeyPath string) (*SealedHeader, []byte, error) {
+	secret, err := ioutil.ReadFile(secretKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to read the secret key of the recipient: %s", err)
+	}
+	if len(secret) != (sodium.BoxSecretKey{}).Size() {
+		return nil, nil, fmt.Errorf("Secret key of the recipient has %d bytes, but a box secret key has %d bytes", len(secret), sodium.BoxSecretKey{}.Size())
+	}
+	secretKey := sodium.BoxSecretKey{Bytes: secret}
+	keyPair := sodium.BoxKP{PublicKey: secretKey.PublicKey(), SecretKey: secretKey}
+	keyID := RecipientKeyID(keyPair.PublicKey.Bytes)
+
+	// the header may have been re-indented, thus it is authenticated on its compact encoding
+	var headerBytes bytes.Buffer
+	err = json.Compact(&headerBytes, c.Header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to parse the header of the container: %s", err)
+	}
+	header := &SealedHeader{}
+	err = json.Unmarshal(headerBytes.Bytes(), header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to parse the header of the container: %s", err)
+	}
+
+	var recipient *Recipient
+	for _, r := range header.Recipients {
+		if r.KeyID == keyID {
+			recipient = r
+			break
+		}
+	}
+	if recipient == nil {
+		return nil, nil, fmt.Errorf("Container was not encrypted to the recipient %s", keyID)
+	}
+
+	key, err := sodium.Bytes(recipient.SealedKey).SealedBoxOpen(keyPair)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to open the content key of recipient %s: %s", keyID, err)
+	}
+	if len(key) != (sodium.AEADCPKey{}).Size() || len(c.Nonce) != (sodium.AEADCPNonce{}).Size() {
+		return nil, nil, fmt.Errorf("Content key or nonce of the container have an invalid size")
+	}
+
+	plaintext, err := sodium.Bytes(c.Ciphertext).AEADCPDecrypt(sodium.Bytes(headerBytes.Bytes()), sodium.AEADCPNonce{Bytes: c.Nonce}, sodium.AEADCPKey{Bytes: key})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Container was altered, its ciphertext or header is not authentic")
+	}
+	if header.FormatVersion != SealedFormatVersion {
+		return nil, nil, fmt.Errorf("Container has format version %d, but only version %d is supported", header.FormatVersion, SealedFormatVersion)
+	}
+	if digest(plaintext) != header.SHA256 {
+		return nil, nil, fmt.Errorf("Decrypted content does not match the digest %s of the header", header.SHA256)
+	}
+
+	return header, plaintext, nil
+}
+
+// Extract writes the decrypted plaintext to the given path. The archive of a directory is unpacked into it
+func Extract(header *SealedHeader, plaintext []byte, path string) error {
+	switch header.Content {
+	case ContentFile:
+		return ioutil.WriteFile(path, plaintext, 0644)
+	case ContentDirectory:
+		return ExtractArchive(plaintext, path)
+	}
+	return fmt.Errorf("Container has the unknown content type %s", header.Content)
+}
+
+// ArchiveDir packs the regular files of the directory into a tar archive. The files are added in lexical order and
+// without modification times, such that the same directory always results in the same archive
+func ArchiveDir(dir string) ([]byte, error) {
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		err = writer.WriteHeader(&tar.Header{Name: filepath.ToSlash(name), Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(content)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return archive.Bytes(), nil
+}
+
+// ExtractArchive unpacks the regular files of a tar archive into the given directory. Directory entries are skipped,
+// all other entries and files outside of the directory are rejected
+func ExtractArchive(archive []byte, dir string) error {
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return fmt.Errorf("Archive contains %s, which is not a regular file", header.Name)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("Archive contains %s, which is outside of the directory", header.Name)
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(path, content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+}
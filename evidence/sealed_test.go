@@ -0,0 +1,105 @@
+package evidence
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jamesruan/sodium"
+)
+
+// recipientKeys writes a new box key pair of a recipient into the directory and returns the paths of the secret and the public key
+func recipientKeys(t *testing.T, dir string, name string) (string, string) {
+	keyPair := sodium.MakeBoxKP()
+	secretKeyPath, publicKeyPath := filepath.Join(dir, name+".sk"), filepath.Join(dir, name+".pk")
+	if err := ioutil.WriteFile(secretKeyPath, keyPair.SecretKey.Bytes, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(publicKeyPath, keyPair.PublicKey.Bytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return secretKeyPath, publicKeyPath
+}
+
+// sealedBundle seals a bundle directory of two files to the given recipients
+func sealedBundle(t *testing.T, dir string, publicKeyPaths ...string) string {
+	bundleDir := filepath.Join(dir, "bundle")
+	if err := os.MkdirAll(bundleDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"bundle.json": "{}", "ch_1.block": "block"} {
+		if err := ioutil.WriteFile(filepath.Join(bundleDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sealedPath, err := Seal(bundleDir, publicKeyPaths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealedPath
+}
+
+func TestSealAndDecrypt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sealed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secretA, publicA := recipientKeys(t, dir, "a")
+	secretB, publicB := recipientKeys(t, dir, "b")
+
+	sealedPath := sealedBundle(t, dir, publicA, publicB)
+	if _, err := os.Stat(filepath.Join(dir, "bundle")); !os.IsNotExist(err) {
+		t.Fatal("Expected the plaintext bundle to be removed after sealing it")
+	}
+	container, err := ReadSealedContainer(sealedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every recipient opens the container on its own
+	for _, secretKeyPath := range []string{secretA, secretB} {
+		header, plaintext, err := container.Decrypt(secretKeyPath)
+		if err != nil {
+			t.Fatalf("Expected the recipient to decrypt the container, got: %s", err)
+		}
+		extracted := filepath.Join(dir, "extracted")
+		if err := Extract(header, plaintext, extracted); err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadFile(filepath.Join(extracted, "ch_1.block"))
+		if err != nil || string(content) != "block" || header.Name != "bundle" || header.Content != ContentDirectory {
+			t.Fatalf("Expected the extracted bundle to match the sealed one, got %q: %v", content, err)
+		}
+		os.RemoveAll(extracted)
+	}
+}
+
+func TestDecryptRejectsForeignKeyAndAlteredHeader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sealed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secretA, publicA := recipientKeys(t, dir, "a")
+	secretB, _ := recipientKeys(t, dir, "b")
+
+	container, err := ReadSealedContainer(sealedBundle(t, dir, publicA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = container.Decrypt(secretB)
+	if err == nil || !strings.HasPrefix(err.Error(), "Container was not encrypted to the recipient") {
+		t.Fatalf("Expected the container to be closed to other recipients, got: %v", err)
+	}
+
+	// the header is readable by everyone, but renaming the bundle breaks the authentication of the ciphertext
+	container.Header = bytes.Replace(container.Header, []byte(`"bundle"`), []byte(`"other"`), 1)
+	_, _, err = container.Decrypt(secretA)
+	if err == nil || err.Error() != "Container was altered, its ciphertext or header is not authentic" {
+		t.Fatalf("Expected the altered header to be detected, got: %v", err)
+	}
+}
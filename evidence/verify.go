@@ -0,0 +1,314 @@
+package evidence
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/bft"
+	"github.com/hyperledger/fabric_judge/comparator"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	"github.com/hyperledger/fabric_judge/raft"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// Outcome states whether the verdict of a bundle could be reproduced
+type Outcome int
+
+const (
+	Confirmed Outcome = iota
+	Refuted
+	Unsupported
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Confirmed:
+		return "CONFIRMED"
+	case Refuted:
+		return "REFUTED"
+	case Unsupported:
+		return "UNSUPPORTED"
+	}
+	return "unknown"
+}
+
+// Result is the outcome of re-checking a bundle together with the reasons for it
+type Result struct {
+	Outcome Outcome
+	Reasons []string
+}
+
+func (r *Result) addReason(format string, args ...interface{}) *Result {
+	r.Reasons = append(r.Reasons, fmt.Sprintf(format, args...))
+	return r
+}
+
+// recheck re-runs a single check of the judge on the ledgers of a bundle
+type recheck func() []*verdicts.Verdict
+
+// rechecks contains the checks of a consensus type, which can be re-run on the incomplete ledgers of a bundle.
+// The authenticity check verifies the Kafka or orderer signatures of all bundled blocks. The judge only runs the other checks
+// after the signatures were verified, thus it is re-run before them, unless it is the check which rendered the verdict
+type rechecks struct {
+	authenticityCheck string
+	authenticity      recheck
+	checks            map[string]recheck
+}
+
+// VerifyBundle re-runs the checks needed to confirm the verdict of the bundle, reusing the checks of the judge.
+// The verdict is confirmed, if the check which rendered it renders the same verdict on the bundled blocks
+func VerifyBundle(bundle *Bundle, kafkaPublicKey string) (*Result, error) {
+	manifest := bundle.Manifest
+	result := &Result{Outcome: Refuted}
+
+	var checks *rechecks
+	switch manifest.ConsensusType {
+	case "kafka":
+		keyBytes, err := ioutil.ReadFile(kafkaPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the Kafka public key: %s", err)
+		}
+		if !containsKeyID(manifest.KeyIDs, KafkaKeyID(keyBytes)) {
+			return result.addReason("The given Kafka public key %s is not among the key ids of the bundle", KafkaKeyID(keyBytes)), nil
+		}
+		checks, err = kafkaRechecks(bundle, kafkaPublicKey)
+		if err != nil {
+			return nil, err
+		}
+	case "etcdraft":
+		var err error
+		checks, err = raftRechecks(bundle)
+		if err != nil {
+			return nil, err
+		}
+	case "BFT":
+		checks = bftRechecks(bundle)
+	default:
+		return nil, fmt.Errorf("Bundle was created for the unsupported consensus type %s", manifest.ConsensusType)
+	}
+
+	check, ok := checks.checks[manifest.Check]
+	if !ok {
+		result.Outcome = Unsupported
+		return result.addReason("Check %s cannot be re-run on the blocks of an evidence bundle", manifest.Check), nil
+	}
+
+	if len(manifest.Redacted) > 0 && !redactableChecks[manifest.Check] {
+		return result.addReason("Bundle redacted the payloads of %d envelopes, but check %s needs them", len(manifest.Redacted), manifest.Check), nil
+	}
+
+	if manifest.Check != checks.authenticityCheck {
+		if verdict := checks.authenticity(); verdict != nil {
+			for _, v := range verdict {
+				result.addReason("Bundled blocks are not authentic: %s", v.EvaluateVerdict())
+			}
+			return result, nil
+		}
+	}
+
+	verdict := check()
+	for _, v := range verdict {
+		if v.Message() == manifest.Verdict.Message && v.Identity() == manifest.Verdict.Identity {
+			result.Outcome = Confirmed
+			return result.addReason("Check %s rendered the verdict again: %s", manifest.Check, v.EvaluateVerdict()), nil
+		}
+	}
+
+	if len(verdict) == 0 {
+		return result.addReason("Check %s did not render any verdict on the bundled blocks", manifest.Check), nil
+	}
+	for _, v := range verdict {
+		result.addReason("Check %s rendered a different verdict: %s", manifest.Check, v.EvaluateVerdict())
+	}
+	return result, nil
+}
+
+func kafkaRechecks(bundle *Bundle, kafkaPublicKey string) (*rechecks, error) {
+	parameters := bundle.Manifest.Parameters
+	sizeAccounting, err := validator.SizeAccountingOfVersion(parameters.OrdererVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	newVerifier := func(blocks []*cb.Block, identity string) (*validator.Verifier, error) {
+		return validator.NewVerifier(blocks, kafkaPublicKey, identity, parameters.MaxBatchSize, parameters.PreferredMaxBytes, parameters.AbsoluteMaxBytes, sizeAccounting)
+	}
+
+	// the Kafka stream can only be replayed on consecutive blocks, thus the block cutting is re-checked on a window of each ledger
+	verifiers := make([]*validator.Verifier, 0, len(bundle.Ledgers))
+	windows := make([]*validator.Verifier, 0, len(bundle.Ledgers))
+	for _, ledger := range bundle.Ledgers {
+		v, err := newVerifier(ledger.Blocks, ledger.Identity)
+		if err != nil {
+			return nil, err
+		}
+		err = redactedLeaves(bundle.Manifest.Redacted, v)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, v)
+
+		window, err := newVerifier(consecutiveWindow(ledger.Blocks), ledger.Identity)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	forEachVerifier := func(check func(v *validator.Verifier) []*verdicts.Verdict) recheck {
+		return func() []*verdicts.Verdict {
+			var result []*verdicts.Verdict
+			for _, v := range verifiers {
+				result = append(result, check(v)...)
+			}
+			return result
+		}
+	}
+
+	return &rechecks{
+		authenticityCheck: backend.CheckKafkaMessages,
+		authenticity: forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+			return v.VerifyKafkaSignatures()
+		}),
+		checks: map[string]recheck{
+			backend.CheckKafkaMessages: forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyKafkaMessages()
+			}),
+			backend.CheckOffsetEquivocation: forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyOffsetEquivocation()
+			}),
+			backend.CheckCreatorSignatures: forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyCreatorSignatures()
+			}),
+			// the ledgers of a bundle contain different blocks, thus the Kafka messages are compared by their offset instead of their position
+			backend.CheckKafkaComparison: func() []*verdicts.Verdict {
+				result := comparator.FindOffsetConflicts(verifiers...)
+				for i := 0; i < len(verifiers); i++ {
+					for j := i + 1; j < len(verifiers); j++ {
+						result = append(result, comparator.NewKafkaComparator(verifiers[i], verifiers[j]).CompareKafkaMetadataBookkeeping()...)
+					}
+				}
+				return result
+			},
+			backend.CheckMerkleBatches: func() []*verdicts.Verdict {
+				return validator.VerifyMerkleBatches(verifiers...)
+			},
+			backend.CheckReplays: func() []*verdicts.Verdict {
+				return comparator.NewReplayDetector(verifiers...).DetectReplays()
+			},
+			backend.CheckMessageSizes: forEachVerifier(func(v *validator.Verifier) []*verdicts.Verdict {
+				return v.VerifyMessageSizes()
+			}),
+			backend.CheckBlockCutting: func() []*verdicts.Verdict {
+				var result []*verdicts.Verdict
+				for _, window := range windows {
+					result = append(result, window.VerifyBlockCuttingOfOrderer()...)
+				}
+				return result
+			},
+		},
+	}, nil
+}
+
+func raftRechecks(bundle *Bundle) (*rechecks, error) {
+	verifiers := make([]*raft.Verifier, 0, len(bundle.Ledgers))
+	for _, ledger := range bundle.Ledgers {
+		v, err := raft.NewVerifier(ledger.Blocks, ledger.Identity)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, v)
+	}
+
+	blockSignatures := func() []*verdicts.Verdict {
+		var result []*verdicts.Verdict
+		for _, v := range verifiers {
+			verdict := verifyHashLinks(v.Blocks, v.Identity, redactedBlocks(bundle.Manifest.Redacted, v.Identity))
+			if verdict == nil {
+				verdict = v.VerifyOrdererSignatures()
+			}
+			result = append(result, verdict...)
+		}
+		return result
+	}
+
+	return &rechecks{
+		authenticityCheck: backend.CheckBlockSignatures,
+		authenticity:      blockSignatures,
+		checks: map[string]recheck{
+			backend.CheckBlockSignatures: blockSignatures,
+			backend.CheckBatchSizes: func() []*verdicts.Verdict {
+				var result []*verdicts.Verdict
+				for _, v := range verifiers {
+					result = append(result, v.VerifyBatchSizes()...)
+				}
+				return result
+			},
+			backend.CheckForks: func() []*verdicts.Verdict {
+				return raft.FindFirstFork(verifiers...)
+			},
+		},
+	}, nil
+}
+
+func bftRechecks(bundle *Bundle) *rechecks {
+	verifiers := make([]*bft.Verifier, 0, len(bundle.Ledgers))
+	for _, ledger := range bundle.Ledgers {
+		verifiers = append(verifiers, bft.NewVerifier(ledger.Blocks, ledger.Identity))
+	}
+
+	quorums := func() []*verdicts.Verdict {
+		var result []*verdicts.Verdict
+		for _, v := range verifiers {
+			verdict := verifyHashLinks(v.Blocks, v.Identity, redactedBlocks(bundle.Manifest.Redacted, v.Identity))
+			if verdict == nil {
+				verdict = v.VerifyQuorumSignatures()
+			}
+			result = append(result, verdict...)
+		}
+		return result
+	}
+
+	return &rechecks{
+		authenticityCheck: backend.CheckQuorums,
+		authenticity:      quorums,
+		checks: map[string]recheck{
+			backend.CheckQuorums: quorums,
+			backend.CheckEquivocations: func() []*verdicts.Verdict {
+				return bft.FindEquivocations(verifiers...)
+			},
+		},
+	}
+}
+
+// verifyHashLinks renders the verdicts of the hash chain check of the judge on the incomplete blocks of a ledger
+func verifyHashLinks(blocks []*cb.Block, identity string, redacted map[uint64]bool) []*verdicts.Verdict {
+	invalid, err := validator.VerifyHashLinks(blocks, redacted)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), identity, 2).WithEvidence(identity, validator.HashChainEvidence(blocks, invalid)...)}
+	}
+	return nil
+}
+
+// consecutiveWindow returns the genesis block followed by the last run of consecutive blocks
+func consecutiveWindow(blocks []*cb.Block) []*cb.Block {
+	start := len(blocks) - 1
+	for start > 1 && blocks[start-1].Header.Number+1 == blocks[start].Header.Number {
+		start--
+	}
+	if start <= 1 {
+		return blocks
+	}
+	return append([]*cb.Block{blocks[0]}, blocks[start:]...)
+}
+
+func containsKeyID(keyIDs []string, keyID string) bool {
+	for _, id := range keyIDs {
+		if id == keyID {
+			return true
+		}
+	}
+	return false
+}
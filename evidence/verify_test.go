@@ -0,0 +1,236 @@
+package evidence
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// equivocatingLedgers returns two ledgers, in which Kafka signed different envelopes at offset 2
+func equivocatingLedgers(f *ledgertest.KafkaFixture) []*backend.Ledger {
+	blocksA := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	blocksB := append(blocksA[:2:2], f.Block(blocksA[1], []ledgertest.Message{{Offset: 2, Data: "forged"}, {Offset: 3}}, 0, 0))
+	return []*backend.Ledger{{Identity: "peerA", Blocks: blocksA}, {Identity: "peerB", Blocks: blocksB}}
+}
+
+// judge runs the phases of the Kafka backend on the ledgers and returns the check and the verdicts of the first failing phase
+func judge(t *testing.T, options *backend.Options, ledgers []*backend.Ledger) (string, []*verdicts.Verdict) {
+	ordering, err := backend.NewBackend("kafka", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ordering.DecodeMetadata(ledgers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, phase := range append(ordering.EvidencePhases(), ordering.CuttingPhases()...) {
+		if verdict := phase.Run(); verdict != nil {
+			return phase.Check, verdict
+		}
+	}
+	t.Fatal("Judge did not render any verdict")
+	return "", nil
+}
+
+// exportBundle judges the ledgers and exports the bundle of the first verdict
+func exportBundle(t *testing.T, dir string, f *ledgertest.KafkaFixture, ledgers []*backend.Ledger, redact bool) string {
+	check, verdict := judge(t, f.Options(), ledgers)
+	exporter, err := NewExporter(filepath.Join(dir, "evidence"), "kafka", f.Options(), ledgers, redact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundleDir, err := exporter.Export(check, verdict[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bundleDir
+}
+
+func verifyBundle(t *testing.T, bundleDir string, kafkaPublicKey string) *Result {
+	bundle, err := ReadBundle(bundleDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := VerifyBundle(bundle, kafkaPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// replaceBlock stores the block in the bundle and records its new digest in the manifest
+func replaceBlock(t *testing.T, bundleDir string, identity string, block *cb.Block) {
+	manifest := readManifest(t, bundleDir)
+	for _, ledger := range manifest.Ledgers {
+		if ledger.Identity != identity {
+			continue
+		}
+		for _, file := range ledger.Blocks {
+			if file.Number != block.Header.Number {
+				continue
+			}
+			blockBytes, err := proto.Marshal(block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ioutil.WriteFile(filepath.Join(bundleDir, filepath.FromSlash(file.Path)), blockBytes, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.SHA256 = digest(blockBytes)
+		}
+	}
+	writeManifest(t, bundleDir, manifest)
+}
+
+func readManifest(t *testing.T, bundleDir string) *Manifest {
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(bundleDir, ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := &Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest
+}
+
+func writeManifest(t *testing.T, bundleDir string, manifest *Manifest) {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(bundleDir, ManifestFile), manifestBytes, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyBundleConfirmsGenuineBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evidence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	bundleDir := exportBundle(t, dir, f, equivocatingLedgers(f), false)
+
+	manifest := readManifest(t, bundleDir)
+	if manifest.Check != backend.CheckKafkaComparison || manifest.Verdict.Identity != "Kafka Cluster" {
+		t.Fatalf("Expected a verdict of %s against the Kafka Cluster, got %s against %s", backend.CheckKafkaComparison, manifest.Check, manifest.Verdict.Identity)
+	}
+
+	result := verifyBundle(t, bundleDir, f.KafkaPublicKey)
+	if result.Outcome != Confirmed {
+		t.Fatalf("Expected the genuine bundle to be confirmed, got %s: %v", result.Outcome, result.Reasons)
+	}
+}
+
+func TestVerifyBundleRefutesTamperedBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evidence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	ledgers := equivocatingLedgers(f)
+	bundleDir := exportBundle(t, dir, f, ledgers, false)
+
+	// the conflicting block of peerB is replaced by the honest block of peerA, thus the bundle no longer proves the equivocation
+	replaceBlock(t, bundleDir, "peerB", ledgers[0].Blocks[2])
+
+	result := verifyBundle(t, bundleDir, f.KafkaPublicKey)
+	if result.Outcome != Refuted {
+		t.Fatalf("Expected the tampered bundle to be refuted, got %s: %v", result.Outcome, result.Reasons)
+	}
+}
+
+func TestVerifyBundleRefutesForeignKafkaKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evidence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	bundleDir := exportBundle(t, dir, f, equivocatingLedgers(f), false)
+
+	other := ledgertest.NewKafkaFixture(t, filepath.Join(dir, "evidence"))
+	result := verifyBundle(t, bundleDir, other.KafkaPublicKey)
+	if result.Outcome != Refuted {
+		t.Fatalf("Expected the bundle to be refuted under a foreign Kafka key, got %s: %v", result.Outcome, result.Reasons)
+	}
+}
+
+func TestVerifyBundleRefutesRedactedBundleWithTamperedOffsets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evidence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	ledgers := []*backend.Ledger{{Identity: "peerA", Blocks: blocks}}
+
+	// a redacted bundle of the honest ledger is forged into evidence of an offset equivocation: the manifest keeps the genuine
+	// leaf hash of the redacted envelope, such that its Merkle proof stays valid, while the unauthenticated offset is changed
+	exporter, err := NewExporter(filepath.Join(dir, "evidence"), "kafka", f.Options(), ledgers, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundleDir, err := exporter.Export(backend.CheckMerkleBatches, verdicts.CreateVerdict("forged", "Kafka Cluster", 0).WithEvidence("peerA", 1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := ReadBundle(bundleDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.Manifest.Redacted) == 0 {
+		t.Fatal("Expected the bundle to redact the envelopes")
+	}
+
+	tampered := proto.Clone(bundle.Ledgers[0].Blocks[2]).(*cb.Block)
+	env := &cb.Envelope{}
+	err = proto.Unmarshal(tampered.Data.Data[0], env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.KafkaPayload.KafkaOffset = 1
+	tampered.Data.Data[0], err = proto.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaceBlock(t, bundleDir, "peerA", tampered)
+
+	bundle.Ledgers[0].Blocks[2] = tampered
+	checks, err := kafkaRechecks(bundle, f.KafkaPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verdict := checks.checks[backend.CheckOffsetEquivocation]()
+	if len(verdict) == 0 {
+		t.Fatal("Expected the tampered offset to render an offset equivocation")
+	}
+	manifest := readManifest(t, bundleDir)
+	manifest.Check = backend.CheckOffsetEquivocation
+	manifest.Verdict.Message = verdict[0].Message()
+	writeManifest(t, bundleDir, manifest)
+
+	result := verifyBundle(t, bundleDir, f.KafkaPublicKey)
+	if result.Outcome != Refuted {
+		t.Fatalf("Expected the redacted bundle with a tampered offset to be refuted, got %s: %v", result.Outcome, result.Reasons)
+	}
+}
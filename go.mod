@@ -19,5 +19,6 @@ require (
 	go.starlark.net v0.0.0-20210305151048-6a590ae7f4eb // indirect
 	golang.org/x/arch v0.0.0-20210222215009-a3652b17bebe // indirect
 	golang.org/x/sys v0.0.0-20210305230114-8fe3ee5dd75b // indirect
+	google.golang.org/grpc v1.27.0
 	google.golang.org/protobuf v1.25.0 // indirect
 )
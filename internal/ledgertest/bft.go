@@ -0,0 +1,74 @@
+package ledgertest
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	ob "github.com/hyperledger/fabric_judge/protos/orderer"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// BFTFixture generates the consenters and the channel config of a BFT channel. Unless stated otherwise, the blocks are signed by all consenters
+type BFTFixture struct {
+	*ordererOrg
+	Consenters []*Orderer
+}
+
+// NewBFTFixture generates the CA of the orderer organization and the given number of consenters
+func NewBFTFixture(t testing.TB, consenters int) *BFTFixture {
+	f := &BFTFixture{ordererOrg: newOrdererOrg(t)}
+	for i := 0; i < consenters; i++ {
+		f.Consenters = append(f.Consenters, f.issue(uint64(i+1)))
+	}
+	return f
+}
+
+func (f *BFTFixture) config() *cb.Config {
+	mapping := make([]*cb.Consenter, 0, len(f.Consenters))
+	for _, consenter := range f.Consenters {
+		mapping = append(mapping, &cb.Consenter{Id: uint32(consenter.ID), Host: fmt.Sprintf("orderer%d", consenter.ID), Port: 7050, MspId: "OrdererMSP", Identity: consenter.certPEM, ClientTlsCert: consenter.certPEM, ServerTlsCert: consenter.certPEM})
+	}
+	return &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{
+		"Orderer": {
+			Groups: map[string]*cb.ConfigGroup{"OrdererOrg": f.group()},
+			Values: map[string]*cb.ConfigValue{
+				"ConsensusType": {Value: marshal(f.t, &ob.ConsensusType{Type: "BFT"})},
+				"Orderers":      {Value: marshal(f.t, &cb.Orderers{ConsenterMapping: mapping})},
+				"BatchSize":     {Value: marshal(f.t, &ob.BatchSize{MaxMessageCount: MaxBatchSize, AbsoluteMaxBytes: 100000, PreferredMaxBytes: 50000})},
+			},
+		},
+	}}}
+}
+
+// Genesis returns the genesis block of the channel
+func (f *BFTFixture) Genesis() *cb.Block {
+	return f.genesis(f.config())
+}
+
+// Chain returns the genesis block followed by a block per entry of txs, which contains the given transactions
+func (f *BFTFixture) Chain(txs ...[]string) []*cb.Block {
+	blocks := []*cb.Block{f.Genesis()}
+	for _, blockTxs := range txs {
+		blocks = append(blocks, f.Block(blocks[len(blocks)-1], f.Consenters, blockTxs...))
+	}
+	return blocks
+}
+
+// Block returns the block following prev, which contains the given transactions and is signed by the given consenters
+func (f *BFTFixture) Block(prev *cb.Block, signers []*Orderer, txs ...string) *cb.Block {
+	block := &cb.Block{Header: &cb.BlockHeader{Number: prev.Header.Number + 1, PreviousHash: validator.BlockHeaderHash(prev.Header)}, Data: &cb.BlockData{Data: f.transactions(txs)}}
+	block.Header.DataHash = validator.BlockDataHash(block.Data)
+
+	signatures := make([]*cb.MetadataSignature, 0, len(signers))
+	for _, signer := range signers {
+		signatures = append(signatures, f.signature(signer, block))
+	}
+	block.Metadata = &cb.BlockMetadata{Metadata: [][]byte{
+		marshal(f.t, &cb.Metadata{Signatures: signatures}),
+		marshal(f.t, &cb.Metadata{Value: marshal(f.t, &cb.LastConfig{Index: 0})}),
+		{},
+		{},
+	}}
+	return block
+}
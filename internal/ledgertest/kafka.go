@@ -0,0 +1,305 @@
+// Package ledgertest builds small Kafka and Raft ledgers for the tests of the judge. All envelopes of a Kafka ledger are signed by an orderer,
+// whose certificate is issued by the root of the channel config, and every block is signed by a generated Kafka key
+package ledgertest
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	kf "github.com/hyperledger/fabric_judge/protos/kafka"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+	ob "github.com/hyperledger/fabric_judge/protos/orderer"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// ChannelName is the channel of all generated ledgers
+const ChannelName = "ch"
+
+// MaxBatchSize is the MaxMessageCount of the channel config
+const MaxBatchSize = 2
+
+// Message is a single Kafka message of a generated block. It is a TTC message for the block TTC, if TTC is set
+type Message struct {
+	Offset int64
+	Config bool
+	TTC    uint64
+	// Data replaces the payload data of a normal envelope, which is derived from its offset by default
+	Data string
+	// Replay is an envelope, whose payload and signature are ordered again at the offset
+	Replay *cb.Envelope
+}
+
+// KafkaFixture generates the keys and the channel config of a Kafka channel
+type KafkaFixture struct {
+	t              testing.TB
+	ordererKey     *ecdsa.PrivateKey
+	creator        []byte
+	kafkaKey       ed25519.PrivateKey
+	config         *cb.Config
+	start          int64
+	KafkaPublicKey string
+}
+
+// NewKafkaFixture generates the keys of a channel and writes the public key of the Kafka Cluster into the given directory
+func NewKafkaFixture(t testing.TB, dir string) *KafkaFixture {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	check(t, err)
+	caTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	check(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	check(t, err)
+
+	ordererKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	check(t, err)
+	ordererTemplate := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "orderer0"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	ordererDER, err := x509.CreateCertificate(rand.Reader, ordererTemplate, caCert, &ordererKey.PublicKey, caKey)
+	check(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	ordererPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ordererDER})
+	mspConfig := marshal(t, &mb.MSPConfig{Type: 0, Config: marshal(t, &mb.FabricMSPConfig{Name: "OrdererMSP", RootCerts: [][]byte{caPEM}})})
+	config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{
+		"Orderer": {
+			Groups: map[string]*cb.ConfigGroup{"OrdererOrg": {Values: map[string]*cb.ConfigValue{"MSP": {Value: mspConfig}}}},
+			Values: map[string]*cb.ConfigValue{
+				"ConsensusType": {Value: marshal(t, &ob.ConsensusType{Type: "kafka"})},
+				"BatchSize":     {Value: marshal(t, &ob.BatchSize{MaxMessageCount: MaxBatchSize, AbsoluteMaxBytes: 100000, PreferredMaxBytes: 50000})},
+			},
+		},
+	}}}
+
+	kafkaPublicKey, kafkaKey, err := ed25519.GenerateKey(rand.Reader)
+	check(t, err)
+	keyPath := filepath.Join(dir, "kafka.pub")
+	check(t, ioutil.WriteFile(keyPath, kafkaPublicKey, 0644))
+
+	return &KafkaFixture{
+		t:              t,
+		ordererKey:     ordererKey,
+		creator:        marshal(t, &mb.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: ordererPEM}),
+		kafkaKey:       kafkaKey,
+		config:         config,
+		start:          time.Now().UnixNano() / int64(validator.KafkaTimestampUnit),
+		KafkaPublicKey: keyPath,
+	}
+}
+
+// Options returns the options of a judge run on the ledgers of the fixture
+func (f *KafkaFixture) Options() *backend.Options {
+	return &backend.Options{
+		ChannelName:       ChannelName,
+		KafkaPublicKey:    f.KafkaPublicKey,
+		MaxBatchSize:      MaxBatchSize,
+		PreferredMaxBytes: 50000,
+		AbsoluteMaxBytes:  100000,
+		OrdererVersion:    validator.DefaultOrdererVersion,
+	}
+}
+
+// Genesis returns the genesis block of the channel
+func (f *KafkaFixture) Genesis() *cb.Block {
+	channelHeader := marshal(f.t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: ChannelName})
+	payload := marshal(f.t, &cb.Payload{Header: &cb.Header{ChannelHeader: channelHeader}, Data: marshal(f.t, &cb.ConfigEnvelope{Config: f.config})})
+	genesis := &cb.Block{Header: &cb.BlockHeader{Number: 0}, Data: &cb.BlockData{Data: [][]byte{marshal(f.t, &cb.Envelope{Payload: payload})}}}
+	genesis.Header.DataHash = validator.BlockDataHash(genesis.Data)
+	genesis.Metadata = f.metadata(0, &kf.KafkaMetadata{})
+	return genesis
+}
+
+// Chain returns the genesis block followed by one block per entry of the spec. Config messages advance the config sequence
+func (f *KafkaFixture) Chain(spec [][]Message) []*cb.Block {
+	blocks := []*cb.Block{f.Genesis()}
+	var configSeq, lastConfig uint64
+	for _, messages := range spec {
+		if messages[0].Config {
+			lastConfig = uint64(len(blocks))
+		}
+		blocks = append(blocks, f.Block(blocks[len(blocks)-1], messages, configSeq, lastConfig))
+		if messages[0].Config {
+			configSeq++
+		}
+	}
+	return blocks
+}
+
+// Block returns the block following prev, which contains the given messages. Kafka signs them under a single Merkle root,
+// thus a block holds at most two messages
+func (f *KafkaFixture) Block(prev *cb.Block, messages []Message, configSeq uint64, lastConfig uint64) *cb.Block {
+	envelopes := make([]*cb.Envelope, len(messages))
+	leaves := make([][]byte, len(messages))
+	for i, m := range messages {
+		var leaf [32]byte
+		if m.TTC != 0 {
+			leaf = sha256.Sum256(f.ttcBytes(m))
+		} else {
+			envelopes[i] = f.envelope(m, configSeq)
+			leaf = sha256.Sum256(validator.GetKafkaSignedDataFromEnvelope(envelopes[i]))
+		}
+		leaves[i] = leaf[:]
+	}
+
+	var root []byte
+	proofs := make([][]byte, len(leaves))
+	switch len(leaves) {
+	case 1:
+		root = leaves[0]
+		proofs[0] = proofBytes(root, nil, 0, 1)
+	case 2:
+		hash := sha256.Sum256(append(append([]byte{}, leaves[0]...), leaves[1]...))
+		root = hash[:]
+		proofs[0] = proofBytes(root, [][]byte{leaves[1]}, 0, 2)
+		proofs[1] = proofBytes(root, [][]byte{leaves[0]}, 1, 2)
+	default:
+		f.t.Fatalf("Block of %d messages is not supported", len(leaves))
+	}
+	signature := ed25519.Sign(f.kafkaKey, root)
+
+	kafkaMetadata := &kf.KafkaMetadata{}
+	data := make([][]byte, 0, len(messages))
+	for i, m := range messages {
+		kafkaMetadata.LastOffsetPersisted = m.Offset
+		if m.TTC != 0 {
+			kafkaMetadata.ReceivedTTCMessage = true
+			kafkaMetadata.TTCPayload = &kf.KafkaPayload{KafkaMerkleProofHeader: proofs[i], KafkaSignatureHeader: signature, ConsumerMessageBytes: f.ttcBytes(m)}
+			continue
+		}
+		envelopes[i].KafkaPayload.KafkaMerkleProofHeader = proofs[i]
+		envelopes[i].KafkaPayload.KafkaSignatureHeader = signature
+		if m.Config {
+			kafkaMetadata.IsConfigMessage = true
+		}
+		data = append(data, marshal(f.t, envelopes[i]))
+	}
+
+	block := &cb.Block{Header: &cb.BlockHeader{Number: prev.Header.Number + 1, PreviousHash: validator.BlockHeaderHash(prev.Header)}, Data: &cb.BlockData{Data: data}}
+	block.Header.DataHash = validator.BlockDataHash(block.Data)
+	block.Metadata = f.metadata(lastConfig, kafkaMetadata)
+	return block
+}
+
+// WriteLedger writes the blocks into the given directory, using the file names read by the judge
+func WriteLedger(t testing.TB, dir string, blocks []*cb.Block) {
+	check(t, os.MkdirAll(dir, 0755))
+	for _, block := range blocks {
+		check(t, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%s_%d.block", ChannelName, block.Header.Number)), marshal(t, block), 0644))
+	}
+}
+
+func (f *KafkaFixture) envelope(m Message, configSeq uint64) *cb.Envelope {
+	signatureHeader := &cb.SignatureHeader{Creator: f.creator, Nonce: []byte(fmt.Sprint(m.Offset))}
+	class := cb.KafkaReg_Payload_NORMAL
+	var payload []byte
+	if m.Config {
+		channelHeader := marshal(f.t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: ChannelName})
+		payload = marshal(f.t, &cb.Payload{Header: &cb.Header{ChannelHeader: channelHeader, SignatureHeader: marshal(f.t, signatureHeader)}, Data: marshal(f.t, &cb.ConfigEnvelope{Config: f.config})})
+		class = cb.KafkaReg_Payload_CONFIG
+	} else {
+		data := m.Data
+		if data == "" {
+			data = fmt.Sprintf("tx%d", m.Offset)
+		}
+		channelHeader := marshal(f.t, &cb.ChannelHeader{Type: int32(cb.HeaderType_MESSAGE), ChannelId: ChannelName})
+		payload = marshal(f.t, &cb.Payload{Header: &cb.Header{ChannelHeader: channelHeader, SignatureHeader: marshal(f.t, signatureHeader)}, Data: []byte(data)})
+	}
+
+	signature := f.sign(payload)
+	if m.Replay != nil {
+		payload, signature = m.Replay.Payload, m.Replay.Signature
+	}
+
+	return &cb.Envelope{
+		Payload:   payload,
+		Signature: signature,
+		KafkaPayload: &cb.KafkaPayload{
+			KafkaOffset:         m.Offset,
+			KafkaTimestamp:      f.start + m.Offset,
+			KafkaRegularMessage: &cb.KafkaReg_Payload{ConfigSeq: configSeq, Class: class},
+		},
+	}
+}
+
+// ttcBytes encodes the TTC message like the consumer message of the Kafka-based orderer: offset, timestamp and the message itself
+func (f *KafkaFixture) ttcBytes(m Message) []byte {
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint64(header[0:8], uint64(m.Offset))
+	binary.BigEndian.PutUint64(header[8:16], uint64(f.start+m.Offset))
+	return append(header, marshal(f.t, &kf.KafkaMessage{Type: &kf.KafkaMessage_TimeToCut{TimeToCut: &kf.KafkaMessageTimeToCut{BlockNumber: m.TTC}}})...)
+}
+
+func (f *KafkaFixture) metadata(lastConfig uint64, kafkaMetadata *kf.KafkaMetadata) *cb.BlockMetadata {
+	return &cb.BlockMetadata{Metadata: [][]byte{
+		{},
+		marshal(f.t, &cb.Metadata{Value: marshal(f.t, &cb.LastConfig{Index: lastConfig})}),
+		{},
+		marshal(f.t, &cb.Metadata{Value: marshal(f.t, kafkaMetadata)}),
+	}}
+}
+
+// sign creates a signature of the orderer
+func (f *KafkaFixture) sign(message []byte) []byte {
+	return signLowS(f.t, f.ordererKey, message)
+}
+
+// signLowS creates a low-S ECDSA signature, like the BCCSP of Fabric
+func signLowS(t testing.TB, key *ecdsa.PrivateKey, message []byte) []byte {
+	hash := sha256.Sum256(message)
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	check(t, err)
+	n := elliptic.P256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+	return marshalASN1(t, struct{ R, S *big.Int }{r, s})
+}
+
+// proofBytes encodes a Merkle proof like the Kafka Cluster: hash size, set size, leaf index and tree size followed by the root and the proof set
+func proofBytes(root []byte, set [][]byte, index int, size int) []byte {
+	proof := make([]byte, 16)
+	binary.BigEndian.PutUint32(proof[0:4], sha256.Size)
+	binary.BigEndian.PutUint32(proof[4:8], uint32(len(set)))
+	binary.BigEndian.PutUint32(proof[8:12], uint32(index))
+	binary.BigEndian.PutUint32(proof[12:16], uint32(size))
+	proof = append(proof, root...)
+	for _, hash := range set {
+		proof = append(proof, hash...)
+	}
+	return append(proof, []byte("SHA-256")...)
+}
+
+func marshal(t testing.TB, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	check(t, err)
+	return data
+}
+
+func marshalASN1(t testing.TB, value interface{}) []byte {
+	data, err := asn1.Marshal(value)
+	check(t, err)
+	return data
+}
+
+func check(t testing.TB, err error) {
+	if err != nil {
+		t.Helper()
+		t.Fatal(err)
+	}
+}
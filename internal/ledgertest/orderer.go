@@ -0,0 +1,99 @@
+package ledgertest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// Orderer is a consenter of a generated Raft or BFT channel
+type Orderer struct {
+	ID      uint64
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	creator []byte
+}
+
+// ordererOrg is the CA of the orderer organization, which issues the certificates of the consenters
+type ordererOrg struct {
+	t      testing.TB
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+	caPEM  []byte
+}
+
+func newOrdererOrg(t testing.TB) *ordererOrg {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	check(t, err)
+	caTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	check(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	check(t, err)
+	return &ordererOrg{t: t, caKey: caKey, caCert: caCert, caPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})}
+}
+
+// issue generates the key and the certificate of the orderer with the given id
+func (o *ordererOrg) issue(id uint64) *Orderer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	check(o.t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(int64(id + 1)), Subject: pkix.Name{CommonName: fmt.Sprintf("orderer%d", id)}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, o.caCert, &key.PublicKey, o.caKey)
+	check(o.t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return &Orderer{
+		ID:      id,
+		key:     key,
+		certPEM: certPEM,
+		creator: marshal(o.t, &mb.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: certPEM}),
+	}
+}
+
+// group returns the config group of the orderer organization
+func (o *ordererOrg) group() *cb.ConfigGroup {
+	mspConfig := marshal(o.t, &mb.MSPConfig{Type: 0, Config: marshal(o.t, &mb.FabricMSPConfig{Name: "OrdererMSP", RootCerts: [][]byte{o.caPEM}})})
+	return &cb.ConfigGroup{Values: map[string]*cb.ConfigValue{"MSP": {Value: mspConfig}}}
+}
+
+// configEnvelope returns the envelope of a config transaction, which sets the given channel config
+func (o *ordererOrg) configEnvelope(config *cb.Config) []byte {
+	channelHeader := marshal(o.t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: ChannelName})
+	payload := marshal(o.t, &cb.Payload{Header: &cb.Header{ChannelHeader: channelHeader}, Data: marshal(o.t, &cb.ConfigEnvelope{Config: config})})
+	return marshal(o.t, &cb.Envelope{Payload: payload})
+}
+
+// genesis returns the genesis block of the channel with the given config
+func (o *ordererOrg) genesis(config *cb.Config) *cb.Block {
+	genesis := &cb.Block{Header: &cb.BlockHeader{Number: 0}, Data: &cb.BlockData{Data: [][]byte{o.configEnvelope(config)}}}
+	genesis.Header.DataHash = validator.BlockDataHash(genesis.Data)
+	genesis.Metadata = &cb.BlockMetadata{Metadata: [][]byte{{}, {}, {}, {}}}
+	return genesis
+}
+
+// transactions returns the envelopes of endorser transactions, which contain the given data
+func (o *ordererOrg) transactions(txs []string) [][]byte {
+	data := make([][]byte, 0, len(txs))
+	for _, tx := range txs {
+		channelHeader := marshal(o.t, &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: ChannelName})
+		data = append(data, marshal(o.t, &cb.Envelope{Payload: marshal(o.t, &cb.Payload{Header: &cb.Header{ChannelHeader: channelHeader}, Data: []byte(tx)})}))
+	}
+	return data
+}
+
+// signature returns the signature of the orderer over the header of the block, as stored in its SIGNATURES metadata
+func (o *ordererOrg) signature(orderer *Orderer, block *cb.Block) *cb.MetadataSignature {
+	signatureHeader := marshal(o.t, &cb.SignatureHeader{Creator: orderer.creator, Nonce: []byte(fmt.Sprint(block.Header.Number))})
+	signedBytes := append(append([]byte{}, signatureHeader...), validator.BlockHeaderBytes(block.Header)...)
+	return &cb.MetadataSignature{SignatureHeader: signatureHeader, Signature: signLowS(o.t, orderer.key, signedBytes)}
+}
@@ -0,0 +1,105 @@
+package ledgertest
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	ob "github.com/hyperledger/fabric_judge/protos/orderer"
+	"github.com/hyperledger/fabric_judge/protos/orderer/etcdraft"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// RaftFixture generates the orderers and the channel config of an etcdraft channel. The blocks are signed by the first consenter
+type RaftFixture struct {
+	*ordererOrg
+	// Consenters are the orderers of the config in effect for the next block
+	Consenters      []*Orderer
+	nextConsenterID uint64
+	raftIndex       uint64
+	lastConfig      uint64
+}
+
+// NewRaftFixture generates the CA of the orderer organization and the given number of consenters
+func NewRaftFixture(t testing.TB, consenters int) *RaftFixture {
+	f := &RaftFixture{
+		ordererOrg:      newOrdererOrg(t),
+		nextConsenterID: 1,
+	}
+	for i := 0; i < consenters; i++ {
+		f.Consenters = append(f.Consenters, f.NewOrderer())
+	}
+	return f
+}
+
+// NewOrderer issues the certificate of an orderer with the next consenter id, which is not yet a consenter of the channel
+func (f *RaftFixture) NewOrderer() *Orderer {
+	orderer := f.issue(f.nextConsenterID)
+	f.nextConsenterID++
+	return orderer
+}
+
+// config returns the channel config, whose consenters are the given orderers
+func (f *RaftFixture) config(consenters []*Orderer) *cb.Config {
+	raftConsenters := make([]*etcdraft.Consenter, 0, len(consenters))
+	for _, consenter := range consenters {
+		raftConsenters = append(raftConsenters, &etcdraft.Consenter{Host: fmt.Sprintf("orderer%d", consenter.ID), Port: 7050, ClientTlsCert: consenter.certPEM, ServerTlsCert: consenter.certPEM})
+	}
+	consensusType := &ob.ConsensusType{Type: "etcdraft", Metadata: marshal(f.t, &etcdraft.ConfigMetadata{Consenters: raftConsenters})}
+	return &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{
+		"Orderer": {
+			Groups: map[string]*cb.ConfigGroup{"OrdererOrg": f.group()},
+			Values: map[string]*cb.ConfigValue{
+				"ConsensusType": {Value: marshal(f.t, consensusType)},
+				"BatchSize":     {Value: marshal(f.t, &ob.BatchSize{MaxMessageCount: MaxBatchSize, AbsoluteMaxBytes: 100000, PreferredMaxBytes: 50000})},
+			},
+		},
+	}}}
+}
+
+// Genesis returns the genesis block of the channel
+func (f *RaftFixture) Genesis() *cb.Block {
+	return f.genesis(f.config(f.Consenters))
+}
+
+// Chain returns the genesis block followed by a block per entry of txs, which contains the given transactions
+func (f *RaftFixture) Chain(txs ...[]string) []*cb.Block {
+	blocks := []*cb.Block{f.Genesis()}
+	for _, blockTxs := range txs {
+		blocks = append(blocks, f.Block(blocks[len(blocks)-1], blockTxs...))
+	}
+	return blocks
+}
+
+// Block returns the block following prev, which contains the given transactions
+func (f *RaftFixture) Block(prev *cb.Block, txs ...string) *cb.Block {
+	return f.signedBlock(prev, f.transactions(txs))
+}
+
+// ConfigBlock returns the config block following prev, which replaces the consenters of the channel. Like Fabric,
+// the Raft metadata of the config block already lists the ids of the new consenters
+func (f *RaftFixture) ConfigBlock(prev *cb.Block, consenters []*Orderer) *cb.Block {
+	f.Consenters = consenters
+	f.lastConfig = prev.Header.Number + 1
+	return f.signedBlock(prev, [][]byte{f.configEnvelope(f.config(consenters))})
+}
+
+func (f *RaftFixture) signedBlock(prev *cb.Block, data [][]byte) *cb.Block {
+	block := &cb.Block{Header: &cb.BlockHeader{Number: prev.Header.Number + 1, PreviousHash: validator.BlockHeaderHash(prev.Header)}, Data: &cb.BlockData{Data: data}}
+	block.Header.DataHash = validator.BlockDataHash(block.Data)
+
+	f.raftIndex++
+	consenterIDs := make([]uint64, 0, len(f.Consenters))
+	for _, consenter := range f.Consenters {
+		consenterIDs = append(consenterIDs, consenter.ID)
+	}
+	raftMetadata := marshal(f.t, &etcdraft.BlockMetadata{ConsenterIds: consenterIDs, NextConsenterId: f.nextConsenterID, RaftIndex: f.raftIndex})
+
+	block.Metadata = &cb.BlockMetadata{Metadata: [][]byte{
+		marshal(f.t, &cb.Metadata{Signatures: []*cb.MetadataSignature{f.signature(f.Consenters[0], block)}}),
+		marshal(f.t, &cb.Metadata{Value: marshal(f.t, &cb.LastConfig{Index: f.lastConfig})}),
+		{},
+		marshal(f.t, &cb.Metadata{Value: raftMetadata}),
+	}}
+	return block
+}
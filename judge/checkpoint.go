@@ -0,0 +1,293 @@
+package judge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	"github.com/hyperledger/fabric_judge/evidence"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// CheckpointFormatVersion is the version of the checkpoint format
+const CheckpointFormatVersion = 2
+
+// Checkpoint records the prefix of the ledger of a single peer, which was verified by a previous run without rendering a verdict.
+// A later run on the same channel starts after this prefix, once it confirmed that the ledger still contains it
+type Checkpoint struct {
+	FormatVersion int                  `json:"format_version"`
+	JudgeVersion  string               `json:"judge_version"`
+	CreatedAt     string               `json:"created_at"`
+	ChannelName   string               `json:"channel_name"`
+	Identity      string               `json:"identity"`
+	ConsensusType string               `json:"consensus_type"`
+	Parameters    CheckpointParameters `json:"parameters"`
+	// Height is the number of verified blocks, thus the last verified block is Height-1
+	Height uint64 `json:"height"`
+	// LastHeaderHash is the header hash of the last verified block, which links to all blocks before it
+	LastHeaderHash string                   `json:"last_header_hash"`
+	State          *backend.CheckpointState `json:"state"`
+}
+
+// CheckpointParameters are the parameters of the run, which the verified prefix depends on.
+// A checkpoint is only resumed by a run with the same parameters
+type CheckpointParameters struct {
+	KafkaKeyID        string `json:"kafka_key_id,omitempty"`
+	MaxBatchSize      int    `json:"max_batch_size"`
+	PreferredMaxBytes int    `json:"preferred_max_bytes"`
+	AbsoluteMaxBytes  int    `json:"absolute_max_bytes"`
+	OrdererVersion    string `json:"orderer_version"`
+	BatchTimeout      string `json:"batch_timeout"`
+	TimingTolerance   string `json:"timing_tolerance"`
+}
+
+func newCheckpointParameters(consensusType string, options *backend.Options) (CheckpointParameters, error) {
+	parameters := CheckpointParameters{
+		MaxBatchSize:      options.MaxBatchSize,
+		PreferredMaxBytes: options.PreferredMaxBytes,
+		AbsoluteMaxBytes:  options.AbsoluteMaxBytes,
+		OrdererVersion:    options.OrdererVersion,
+		BatchTimeout:      options.BatchTimeout.String(),
+		TimingTolerance:   options.TimingTolerance.String(),
+	}
+	if consensusType == "kafka" {
+		keyBytes, err := ioutil.ReadFile(options.KafkaPublicKey)
+		if err != nil {
+			return parameters, fmt.Errorf("Unable to read the Kafka public key: %s", err)
+		}
+		parameters.KafkaKeyID = evidence.KafkaKeyID(keyBytes)
+	}
+	return parameters, nil
+}
+
+// checkpointPath returns the path of the checkpoint of the given peer. Every channel has its own directory
+func checkpointPath(dir string, channelName string, identity string) string {
+	return filepath.Join(dir, url.PathEscape(channelName), url.PathEscape(identity)+".json")
+}
+
+// ReadCheckpoint reads the checkpoint of the given peer and channel. It returns nil, if no checkpoint was written yet
+func ReadCheckpoint(dir string, channelName string, identity string) (*Checkpoint, error) {
+	checkpointBytes, err := ioutil.ReadFile(checkpointPath(dir, channelName, identity))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	err = json.Unmarshal(checkpointBytes, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse the checkpoint of %s: %s", identity, err)
+	}
+	return checkpoint, nil
+}
+
+// Write writes the checkpoint into the given directory. The previous checkpoint is replaced atomically,
+// such that an interrupted run leaves either of them behind
+func (c *Checkpoint) Write(dir string) error {
+	path := checkpointPath(dir, c.ChannelName, c.Identity)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	checkpointBytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(path+".tmp", checkpointBytes, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// prefixHash returns the hex encoded header hash of block height-1, after checking that every block up to it references its predecessor
+// and matches the DataHash of its header. The genesis and config blocks of the prefix are verified again when resuming, thus their data
+// must be covered by the header hash as well
+func prefixHash(blocks []*cb.Block, height uint64) (string, error) {
+	if height == 0 || uint64(len(blocks)) < height {
+		return "", fmt.Errorf("Ledger contains %d blocks, but the checkpoint covers %d", len(blocks), height)
+	}
+	for i := uint64(0); i < height; i++ {
+		if i > 0 && !bytes.Equal(blocks[i].Header.PreviousHash, validator.BlockHeaderHash(blocks[i-1].Header)) {
+			return "", fmt.Errorf("PreviousHash of block %d does not match the header of block %d", i, i-1)
+		}
+		if !bytes.Equal(blocks[i].Header.DataHash, validator.BlockDataHash(blocks[i].Data)) {
+			return "", fmt.Errorf("DataHash of block %d does not match its data", i)
+		}
+	}
+	return hex.EncodeToString(validator.BlockHeaderHash(blocks[height-1].Header)), nil
+}
+
+// readCheckpoints reads the checkpoints of all ledgers of the job and returns them, if the run can resume from them.
+// This is the case, if every ledger still contains the prefix of its checkpoint and all checkpoints were written at the same height
+// with the parameters of this run. Otherwise, nil is returned and the ledgers are verified from the genesis block
+func readCheckpoints(job *Job, options *backend.Options, consensusType string, ledgers []*backend.Ledger) ([]*Checkpoint, error) {
+	parameters, err := newCheckpointParameters(consensusType, options)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(ledgers))
+	for _, ledger := range ledgers {
+		checkpoint, err := ReadCheckpoint(job.CheckpointDir, job.Options.ChannelName, ledger.Identity)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint == nil {
+			job.progress("No checkpoint of " + ledger.Identity + " was found, verifying all blocks")
+			return nil, nil
+		}
+		if checkpoint.FormatVersion != CheckpointFormatVersion || checkpoint.ConsensusType != consensusType || checkpoint.Parameters != parameters || checkpoint.State == nil {
+			job.progress("Checkpoint of " + ledger.Identity + " was written with different parameters, verifying all blocks")
+			return nil, nil
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	// the ledgers are compared block by block, thus all of them have to resume at the same block
+	height := checkpoints[0].Height
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Height != height || checkpoint.State.ResumeHeight(height) != checkpoints[0].State.ResumeHeight(height) {
+			job.progress("Checkpoints of the peers were written at different heights, verifying all blocks")
+			return nil, nil
+		}
+	}
+
+	for i, ledger := range ledgers {
+		hash, err := prefixHash(ledger.Blocks, height)
+		if err == nil && hash != checkpoints[i].LastHeaderHash {
+			err = fmt.Errorf("Header hash of block %d does not match the checkpoint", height-1)
+		}
+		if err == nil && uint64(len(ledger.Blocks)) > height && !bytes.Equal(ledger.Blocks[height].Header.PreviousHash, validator.BlockHeaderHash(ledger.Blocks[height-1].Header)) {
+			err = fmt.Errorf("PreviousHash of block %d does not match the header of block %d", height, height-1)
+		}
+		if err != nil {
+			job.progress("Ledger of " + ledger.Identity + " no longer contains the prefix of its checkpoint, verifying all blocks: " + err.Error())
+			return nil, nil
+		}
+	}
+	return checkpoints, nil
+}
+
+// resume decodes the ledgers starting at the block, at which the run resumes from the checkpoints, and returns them together with this block.
+// If the ledgers cannot be resumed, nil is returned
+func resume(job *Job, options *backend.Options, ordering backend.ResumableBackend, consensusType string, ledgers []*backend.Ledger) ([]*backend.Ledger, uint64, error) {
+	checkpoints, err := readCheckpoints(job, options, consensusType, ledgers)
+	if checkpoints == nil || err != nil {
+		return nil, 0, err
+	}
+
+	height := checkpoints[0].Height
+	states := make([]*backend.CheckpointState, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		states = append(states, checkpoint.State)
+	}
+	judged, from, err := resumeStates(ordering, ledgers, height, states)
+	if err != nil {
+		job.progress("Unable to resume from the checkpoint, verifying all blocks: " + err.Error())
+		return nil, 0, nil
+	}
+
+	job.progress(fmt.Sprintf("Resuming from the checkpoints at height %d, verifying the blocks starting with block %d", height, from))
+	return judged, from, nil
+}
+
+// resumeStates decodes the ledgers starting at the block, at which the checks continue with the given states of the ledgers at the given height,
+// and returns them together with this block. The ledgers must contain all blocks below the height, which were verified with these states
+func resumeStates(ordering backend.ResumableBackend, ledgers []*backend.Ledger, height uint64, states []*backend.CheckpointState) ([]*backend.Ledger, uint64, error) {
+	from := states[0].ResumeHeight(height)
+	judged := make([]*backend.Ledger, 0, len(ledgers))
+	for i, ledger := range ledgers {
+		if states[i].ResumeHeight(height) != from {
+			return nil, 0, fmt.Errorf("Ledger of %s resumes at block %d, but the one of %s at block %d", ledger.Identity, states[i].ResumeHeight(height), ledgers[0].Identity, from)
+		}
+		if uint64(len(ledger.Blocks)) > height && !bytes.Equal(ledger.Blocks[height].Header.PreviousHash, validator.BlockHeaderHash(ledger.Blocks[height-1].Header)) {
+			return nil, 0, fmt.Errorf("PreviousHash of block %d of %s does not match the header of block %d", height, ledger.Identity, height-1)
+		}
+		judged = append(judged, resumedLedger(ledger, from))
+	}
+	err := ordering.DecodeMetadata(judged)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, state := range states {
+		err = ordering.Resume(i, height, state)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return judged, from, nil
+}
+
+// resumedLedger returns the blocks of the ledger, which are verified when resuming at the given block: the genesis block and all config blocks
+// before it, which carry the channel config in effect and the config sequence, followed by the blocks starting with the given one
+func resumedLedger(ledger *backend.Ledger, from uint64) *backend.Ledger {
+	blocks := make([]*cb.Block, 0)
+	for i, block := range ledger.Blocks {
+		if i == 0 || uint64(i) >= from || channelconfig.IsConfigBlock(block) {
+			blocks = append(blocks, block)
+		}
+	}
+	return &backend.Ledger{Identity: ledger.Identity, Blocks: blocks}
+}
+
+// writeCheckpoints records the common height of the ledgers, which were verified without rendering a verdict.
+// If the run was resumed, the checkpoints are only replaced once all ledgers contain blocks starting with the resumed one
+func writeCheckpoints(job *Job, options *backend.Options, ordering backend.ResumableBackend, consensusType string, ledgers []*backend.Ledger, resumedFrom uint64) error {
+	parameters, err := newCheckpointParameters(consensusType, options)
+	if err != nil {
+		return err
+	}
+
+	height := uint64(len(ledgers[0].Blocks))
+	for _, ledger := range ledgers {
+		if uint64(len(ledger.Blocks)) < height {
+			height = uint64(len(ledger.Blocks))
+		}
+	}
+	if height <= resumedFrom {
+		job.progress("No blocks were appended to all ledgers since the checkpoints")
+		return nil
+	}
+
+	for i, ledger := range ledgers {
+		hash, err := prefixHash(ledger.Blocks, height)
+		if err != nil {
+			job.progress("Unable to write the checkpoint of " + ledger.Identity + ": " + err.Error())
+			continue
+		}
+		state, err := ordering.CheckpointState(i, height)
+		if err != nil {
+			return err
+		}
+		checkpoint := &Checkpoint{
+			FormatVersion:  CheckpointFormatVersion,
+			JudgeVersion:   Version,
+			CreatedAt:      time.Now().UTC().Format(time.RFC3339),
+			ChannelName:    job.Options.ChannelName,
+			Identity:       ledger.Identity,
+			ConsensusType:  consensusType,
+			Parameters:     parameters,
+			Height:         height,
+			LastHeaderHash: hash,
+			State:          state,
+		}
+		err = checkpoint.Write(job.CheckpointDir)
+		if err != nil {
+			return err
+		}
+	}
+	job.progress("Checkpoints written at height " + strconv.FormatUint(height, 10))
+	return nil
+}
@@ -0,0 +1,141 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+// runJob judges the ledgers in the given directories and returns the report together with the progress of the run
+func runJob(t *testing.T, f *ledgertest.KafkaFixture, checkpointDir string, blockDirs ...string) (*Report, string) {
+	var progress []string
+	job := &Job{
+		Options:       f.Options(),
+		CheckpointDir: checkpointDir,
+		Progress: func(message string) {
+			progress = append(progress, message)
+		},
+	}
+	for i, blockDir := range blockDirs {
+		job.Ledgers = append(job.Ledgers, &LedgerSource{Identity: "peer" + string(rune('A'+i)), BlockDir: blockDir})
+	}
+	report, err := Run(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report, strings.Join(progress, "\n")
+}
+
+func TestResumeFromCheckpoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}, {{Offset: 5}, {Offset: 6}}, {{Offset: 7}, {Offset: 8}}})
+	dirA, dirB, checkpointDir := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "checkpoints")
+	ledgertest.WriteLedger(t, dirA, blocks[:5])
+	ledgertest.WriteLedger(t, dirB, blocks[:5])
+
+	report, progress := runJob(t, f, checkpointDir, dirA, dirB)
+	if !report.Consistent || !strings.Contains(progress, "Checkpoints written at height 5") {
+		t.Fatalf("Expected the consistent ledgers to be checkpointed, got: %v\n%s", report.Verdicts, progress)
+	}
+
+	ledgertest.WriteLedger(t, dirA, blocks)
+	ledgertest.WriteLedger(t, dirB, blocks)
+	report, progress = runJob(t, f, checkpointDir, dirA, dirB)
+	if !report.Consistent || !strings.Contains(progress, "Resuming from the checkpoints at height 5") {
+		t.Fatalf("Expected the run to resume from the checkpoints, got: %v\n%s", report.Verdicts, progress)
+	}
+}
+
+func TestResumeFallsBackOnTamperedPrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}, {{Offset: 5}, {Offset: 6}}})
+	dirA, dirB, checkpointDir := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "checkpoints")
+	ledgertest.WriteLedger(t, dirA, blocks)
+	ledgertest.WriteLedger(t, dirB, blocks)
+
+	report, progress := runJob(t, f, checkpointDir, dirA, dirB)
+	if !report.Consistent || !strings.Contains(progress, "Checkpoints written at height 5") {
+		t.Fatalf("Expected the consistent ledgers to be checkpointed, got: %v\n%s", report.Verdicts, progress)
+	}
+
+	// the config block is reused to rebuild the channel config when resuming, thus replacing its data behind an unchanged header
+	// must not be accepted on the grounds of the checkpoint
+	tampered := proto.Clone(blocks[2]).(*cb.Block)
+	env := &cb.Envelope{}
+	err = proto.Unmarshal(tampered.Data.Data[0], env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.Signature = append([]byte{}, env.Signature...)
+	env.Signature[len(env.Signature)-1] ^= 0xff
+	tampered.Data.Data[0], err = proto.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledgertest.WriteLedger(t, dirB, []*cb.Block{tampered})
+
+	report, progress = runJob(t, f, checkpointDir, dirA, dirB)
+	if strings.Contains(progress, "Resuming from the checkpoints") || !strings.Contains(progress, "no longer contains the prefix of its checkpoint, verifying all blocks") {
+		t.Fatalf("Expected the run to verify all blocks, got:\n%s", progress)
+	}
+	if report.Consistent {
+		t.Fatal("Expected the full verification to render a verdict on the tampered config block")
+	}
+}
+
+func TestReplayAcrossCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}})
+	dirA, dirB, checkpointDir := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "checkpoints")
+	ledgertest.WriteLedger(t, dirA, blocks)
+	ledgertest.WriteLedger(t, dirB, blocks)
+
+	report, progress := runJob(t, f, checkpointDir, dirA, dirB)
+	if !report.Consistent || !strings.Contains(progress, "Checkpoints written at height 4") {
+		t.Fatalf("Expected the consistent ledgers to be checkpointed, got: %v\n%s", report.Verdicts, progress)
+	}
+
+	// the first envelope of block 1 is ordered again after the checkpoint
+	replayed := &cb.Envelope{}
+	err = proto.Unmarshal(blocks[1].Data.Data[0], replayed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := f.Block(blocks[3], []ledgertest.Message{{Offset: 5, Replay: replayed}, {Offset: 6}}, 1, 2)
+	ledgertest.WriteLedger(t, dirA, []*cb.Block{replay})
+	ledgertest.WriteLedger(t, dirB, []*cb.Block{replay})
+
+	report, progress = runJob(t, f, checkpointDir, dirA, dirB)
+	if !strings.Contains(progress, "Resuming from the checkpoints at height 4") {
+		t.Fatalf("Expected the run to resume from the checkpoints, got:\n%s", progress)
+	}
+	if report.Consistent || report.Check != backend.CheckReplays || !strings.Contains(report.Verdicts[0].Message, "at offset 0 (block 1 of peerA) and at offset 5 (block 4 of peerA)") {
+		t.Fatalf("Expected the replay of the envelope before the checkpoint to be detected, got %s: %v", report.Check, report.Verdicts)
+	}
+}
@@ -0,0 +1,191 @@
+package judge
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	proto "github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+// ledgerFeed reads the blocks, which were appended to the ledger of a peer since the last read
+type ledgerFeed interface {
+	// next returns the new blocks. A block, which is still being written, is returned by a later call
+	next() ([]*cb.Block, error)
+}
+
+// newLedgerFeed creates the feed of the given ledger. It is either a directory of block files as read by Run,
+// or the blockfile storage of a Fabric peer, i.e. a single blockfile or the chain directory containing the blockfiles
+func newLedgerFeed(path string, channelName string) (ledgerFeed, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return &blockfileFeed{path: path}, nil
+	}
+	_, err = os.Stat(filepath.Join(path, blockfileName(0)))
+	if err == nil {
+		return &blockfileFeed{dir: path}, nil
+	}
+	return &blockDirFeed{dir: path, channelName: channelName}, nil
+}
+
+// blockDirFeed reads the block files of a directory, which are numbered consecutively
+type blockDirFeed struct {
+	dir         string
+	channelName string
+	height      int
+}
+
+func (f *blockDirFeed) next() ([]*cb.Block, error) {
+	blocks := make([]*cb.Block, 0)
+	for {
+		blockData, err := ioutil.ReadFile(blockFile(f.dir, f.channelName, f.height))
+		if os.IsNotExist(err) {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read file: %s", err)
+		}
+
+		// a block file, which does not parse, is assumed to be written at the moment. The metadata is written last,
+		// thus a truncated file may parse without it or with only a part of its entries
+		block := new(cb.Block)
+		if proto.Unmarshal(blockData, block) != nil || block.Header == nil || block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_ORDERER) {
+			return blocks, nil
+		}
+		if block.Header.Number != uint64(f.height) {
+			return nil, fmt.Errorf("Block file %s contains block %d", blockFile(f.dir, f.channelName, f.height), block.Header.Number)
+		}
+		blocks = append(blocks, block)
+		f.height++
+	}
+}
+
+// blockfileFeed reads the blockfiles of a Fabric peer. Every block is stored with its length as varint prefix,
+// and a new blockfile is started once the current one has reached its maximum size
+type blockfileFeed struct {
+	// dir is the chain directory. If it is empty, only the single blockfile at path is read
+	dir    string
+	path   string
+	file   int
+	offset int64
+	height uint64
+}
+
+func blockfileName(number int) string {
+	return fmt.Sprintf("blockfile_%06d", number)
+}
+
+func (f *blockfileFeed) next() ([]*cb.Block, error) {
+	blocks := make([]*cb.Block, 0)
+	for {
+		path := f.current()
+		data, err := readFrom(path, f.offset)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read blockfile: %s", err)
+		}
+
+		for pos := 0; pos < len(data); {
+			length, n := proto.DecodeVarint(data[pos:])
+			if n == 0 || pos+n+int(length) > len(data) {
+				// the last block is written at the moment
+				return blocks, nil
+			}
+			block, err := deserializeBlock(data[pos+n : pos+n+int(length)])
+			if err != nil {
+				return nil, fmt.Errorf("Unable to parse block %d of blockfile %s: %s", f.height, path, err)
+			}
+			if block.Header.Number != f.height {
+				return nil, fmt.Errorf("Blockfile %s contains block %d, but block %d was expected", path, block.Header.Number, f.height)
+			}
+			blocks = append(blocks, block)
+			pos += n + int(length)
+			f.offset += int64(n) + int64(length)
+			f.height++
+		}
+
+		// the peer only continues with the next blockfile, once the current one is complete
+		if f.dir == "" {
+			return blocks, nil
+		}
+		_, err = os.Stat(filepath.Join(f.dir, blockfileName(f.file+1)))
+		if err != nil {
+			return blocks, nil
+		}
+		f.file++
+		f.offset = 0
+	}
+}
+
+// readFrom reads the file from the given offset to its end
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(file)
+}
+
+func (f *blockfileFeed) current() string {
+	if f.dir == "" {
+		return f.path
+	}
+	return filepath.Join(f.dir, blockfileName(f.file))
+}
+
+// deserializeBlock decodes a block in the serialization of the Fabric block storage, which encodes the fields of
+// header, data and metadata one after another instead of marshalling the block message
+func deserializeBlock(serialized []byte) (*cb.Block, error) {
+	buf := proto.NewBuffer(serialized)
+	block := &cb.Block{Header: &cb.BlockHeader{}, Data: &cb.BlockData{}, Metadata: &cb.BlockMetadata{}}
+
+	var err error
+	block.Header.Number, err = buf.DecodeVarint()
+	if err != nil {
+		return nil, err
+	}
+	block.Header.DataHash, err = buf.DecodeRawBytes(false)
+	if err != nil {
+		return nil, err
+	}
+	block.Header.PreviousHash, err = buf.DecodeRawBytes(false)
+	if err != nil {
+		return nil, err
+	}
+
+	numItems, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < numItems; i++ {
+		data, err := buf.DecodeRawBytes(false)
+		if err != nil {
+			return nil, err
+		}
+		block.Data.Data = append(block.Data.Data, data)
+	}
+
+	numItems, err = buf.DecodeVarint()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < numItems; i++ {
+		metadata, err := buf.DecodeRawBytes(false)
+		if err != nil {
+			return nil, err
+		}
+		block.Metadata.Metadata = append(block.Metadata.Metadata, metadata)
+	}
+
+	return block, nil
+}
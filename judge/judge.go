@@ -1,110 +1,273 @@
 package judge
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io/ioutil"
-	"log"
+	"path/filepath"
 	"strconv"
 
 	proto "github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric_judge/comparator"
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	"github.com/hyperledger/fabric_judge/evidence"
 	cb "github.com/hyperledger/fabric_judge/protos/common"
-	validator "github.com/hyperledger/fabric_judge/validator"
 	"github.com/hyperledger/fabric_judge/verdicts"
 )
 
-//export VerifyConsistency
-func VerifyConsistency(blockDir1 string, blockDir2 string, identity1 string, identity2 string, channelName string, kafkaPublicKey string, maxBatchSize int, preferredBlockSize int) {
+// Version is the version of the judge, which is recorded in the signed verdict certificates
+const Version = "1.0.0"
 
-	println("Reading and parsing received blocks")
+// Run performs a complete judge run on the ledgers of the job and returns its report.
+// The run is cancelled between two phases, if the context is done
+func Run(ctx context.Context, job *Job) (*Report, error) {
+	if len(job.Ledgers) < 2 {
+		return nil, fmt.Errorf("The judge needs the ledgers of at least two peers, but %d were given", len(job.Ledgers))
+	}
 
-	blocks1 := getBlocksFromDir(blockDir1, channelName)
-	blocks2 := getBlocksFromDir(blockDir2, channelName)
+	job.progress("Reading and parsing received blocks")
 
-	verifier1 := validator.NewVerifier(blocks1, kafkaPublicKey, identity1, maxBatchSize, preferredBlockSize)
-	verifier2 := validator.NewVerifier(blocks2, kafkaPublicKey, identity2, maxBatchSize, preferredBlockSize)
+	// the notices of the checks are reported as progress of the job
+	options := &backend.Options{}
+	*options = *job.Options
+	options.Progress = job.progress
+	ledgers := make([]*backend.Ledger, 0, len(job.Ledgers))
+	blockDirs := make([]string, 0, len(job.Ledgers))
+	for _, source := range job.Ledgers {
+		blocks, err := getBlocksFromDir(source.BlockDir, options.ChannelName)
+		if err != nil {
+			return nil, err
+		}
+		ledgers = append(ledgers, &backend.Ledger{Identity: source.Identity, Blocks: blocks})
+		blockDirs = append(blockDirs, source.BlockDir)
+	}
 
-	println("Blocks are successfully parsed\n")
+	// The consensus type of the channel determines, which metadata the orderers write into the blocks and how they have to be verified
+	consensusType, err := getConsensusType(ledgers[0].Blocks)
+	if err != nil {
+		return nil, err
+	}
+	if consensusType == "kafka" {
+		err = options.FillBatchSize(ledgers[0].Blocks[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	ordering, err := backend.NewBackend(consensusType, options)
+	if err != nil {
+		return nil, err
+	}
 
-	// Verify all merkle proofs, kafka signatures and whether the sequence numbers are incremented sequentially
-	// Here, there are two possible verdicts:
-	// 1. 	Peer accepts block containing invalid merkle proofs, kafka signatures or inconsistent seq. numbers
-	// 		In this case, we blame both orderer and peer
-	// 2. 	Inconsistency is only shown in the last block:
-	// 		Here we assume, that the peer actually followed the protocol and shut down after receiving an invalid kafka message.
+	// The blocks verified by a previous run are skipped, if the ledgers still contain the prefix recorded in the checkpoints
+	resumable, isResumable := ordering.(backend.ResumableBackend)
+	if job.CheckpointDir != "" && !isResumable {
+		job.progress("Checkpoints are not supported for consensus type " + consensusType + ", verifying all blocks")
+	}
+	var judged []*backend.Ledger
+	var resumedFrom uint64
+	if job.CheckpointDir != "" && isResumable {
+		judged, resumedFrom, err = resume(job, options, resumable, consensusType, ledgers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if judged == nil {
+		err = ordering.DecodeMetadata(ledgers)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	println("Verifying Merkle-Proofs and signatures of all Kafka messages. Furthermore, we verify that the Kafka sequence numbers are sorted correctly")
+	job.progress("Blocks are successfully parsed, channel is ordered by " + ordering.ConsensusType() + "\n")
 
-	verdict := verifier1.VerifyKafkaMessages()
-	evaluateVerdict(verdict)
+	report := newReport(consensusType, options, ledgers)
 
-	verdict = verifier2.VerifyKafkaMessages()
-	evaluateVerdict(verdict)
+	// If requested, every verdict is exported as an evidence bundle, which can be handed to the other organizations instead of the complete ledgers
+	var exporter *evidence.Exporter
+	if job.EvidenceDir != "" {
+		exporter, err = evidence.NewExporter(job.EvidenceDir, consensusType, options, ledgers, job.Redact)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	println("Verification successfully complete\n")
+	// First, the evidence of the ordering service is verified. Afterwards, we can rely on the ordering and verify that the blocks were cut correctly
 
-	// Here we can assume, that both peers received the kafka messages in the intended order (because kafka seq. numbers are sorted sequentially)
-	// Thus we can now check, if the Kafka Cluster (viewed as a single entity) signed two different messages with the same sequence number
-	// In this case, we obviously render a verdict against the Kafka Cluster
+	err = runPhases(ctx, ordering.EvidencePhases(), job, exporter, report)
+	if err == nil && report.verdict == nil {
+		err = runPhases(ctx, ordering.CuttingPhases(), job, exporter, report)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	println("Comparing Kafka messages of both blocks to check, if the same sequence number was used on different blocks")
+	// The common prefix of consistent ledgers is recorded, such that the next run only verifies the blocks appended in the meantime
+	if job.CheckpointDir != "" && isResumable && report.verdict == nil {
+		err = writeCheckpoints(job, options, resumable, consensusType, ledgers, resumedFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	kafkaComparator := comparator.NewKafkaComparator(verifier1, verifier2)
-	verdict = kafkaComparator.CompareKafkaMessages()
-	evaluateVerdict(verdict)
+	// The signed certificate is written for consistent ledgers as well, such that the result of the run can be presented to others
+	if job.SigningKey != "" {
+		err = writeCertificate(job.SigningKey, job.CertificatePath, consensusType, options, ledgers, blockDirs, report.Check, report.verdict)
+		if err != nil {
+			return nil, err
+		}
+		report.Certificate, err = sealEvidence(job.CertificatePath, job.Recipients)
+		if err != nil {
+			return nil, err
+		}
+		job.progress("Signed verdict certificate written to " + report.Certificate)
+	}
 
-	println("No irregularity was found\n")
+	return report, nil
+}
 
-	// At this point, the only thing left to do is to verify that the orderer cut his blocks according to the given Block-Cutting algorithm
+// runPhases runs the phases until one of them renders a verdict, which is recorded in the report together with the check of the phase
+func runPhases(ctx context.Context, phases []*backend.Phase, job *Job, exporter *evidence.Exporter, report *Report) error {
+	for _, phase := range phases {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		job.progress(phase.Description)
 
-	println("Verifying that the orderer has cut the blocks correctly")
+		verdict := phase.Run()
+		report.addPhase(phase, verdict == nil)
+		if verdict != nil {
+			report.addVerdicts(phase.Check, verdict)
+			if exporter != nil {
+				return exportVerdict(exporter, phase.Check, job, report)
+			}
+			return nil
+		}
+
+		job.progress(phase.Success + "\n")
+	}
+	return nil
+}
+
+// writeCertificate signs the verdicts of the run together with the digests of the input ledgers and writes the certificate
+func writeCertificate(signingKey string, certificatePath string, consensusType string, options *backend.Options, ledgers []*backend.Ledger, blockDirs []string, check string, verdict []*verdicts.Verdict) error {
+	certificate, err := evidence.NewCertificate(Version, consensusType, options)
+	if err != nil {
+		return err
+	}
+	for i, ledger := range ledgers {
+		digest, err := ledgerDigest(blockDirs[i], options.ChannelName, len(ledger.Blocks))
+		if err != nil {
+			return err
+		}
+		certificate.AddLedger(ledger, digest)
+	}
+	certificate.AddVerdicts(check, verdict)
+
+	signed, err := certificate.Sign(signingKey)
+	if err != nil {
+		return err
+	}
+	return signed.Write(certificatePath)
+}
+
+// ConsensusType reads the consensus type of the channel from the genesis block in the block directory of the ledger
+func ConsensusType(source *LedgerSource, channelName string) (string, error) {
+	genesis, err := computeBlockFromFile(blockFile(source.BlockDir, channelName, 0))
+	if err != nil {
+		return "", err
+	}
+	return getConsensusType([]*cb.Block{genesis})
+}
 
-	verdict = verifier1.VerifyBlockCuttingOfOrderer()
-	evaluateVerdict(verdict)
+// getConsensusType reads the consensus type from the channel config of the genesis block
+func getConsensusType(blocks []*cb.Block) (string, error) {
+	if len(blocks) == 0 {
+		return "", fmt.Errorf("Ledger does not contain any blocks")
+	}
+	config, err := channelconfig.GetConfigFromBlock(blocks[0])
+	if err != nil {
+		return "", err
+	}
+	consensusType, err := channelconfig.GetConsensusType(config)
+	if err != nil {
+		return "", err
+	}
+	return consensusType.Type, nil
+}
 
-	verdict = verifier2.VerifyBlockCuttingOfOrderer()
-	evaluateVerdict(verdict)
+// exportVerdict writes an evidence bundle for each verdict of the report
+func exportVerdict(exporter *evidence.Exporter, check string, job *Job, report *Report) error {
+	for i, v := range report.verdict {
+		bundleDir, err := exporter.Export(check, v)
+		if err != nil {
+			return fmt.Errorf("Unable to export evidence bundle: %s", err)
+		}
+		report.Verdicts[i].Bundle, err = sealEvidence(bundleDir, job.Recipients)
+		if err != nil {
+			return err
+		}
+		job.progress("Evidence bundle written to " + report.Verdicts[i].Bundle)
+	}
+	return nil
+}
 
-	println("Orderer cut blocks following the Block-Cutting algorithm")
+// sealEvidence encrypts the bundle or certificate at the given path to the recipients and returns the path of the encrypted container.
+// Without recipients, the evidence is kept in plaintext
+func sealEvidence(path string, recipients []string) (string, error) {
+	if len(recipients) == 0 {
+		return path, nil
+	}
+	return evidence.Seal(path, recipients)
 }
 
-func evaluateVerdict(verdict []*verdicts.Verdict) {
-	if verdict != nil {
-		for _, v := range verdict {
-			log.Println(v.EvaluateVerdict())
+// ledgerDigest computes the SHA-256 digest of the concatenated block files of a ledger
+func ledgerDigest(dir string, channelName string, height int) (string, error) {
+	h := sha256.New()
+	for i := 0; i < height; i++ {
+		blockData, err := ioutil.ReadFile(blockFile(dir, channelName, i))
+		if err != nil {
+			return "", err
 		}
-		log.Fatal("Inconsistency in blocks is ascertained, exiting...")
+		h.Write(blockData)
 	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-func getBlocksFromDir(dir string, channelName string) []*cb.Block {
+func getBlocksFromDir(dir string, channelName string) ([]*cb.Block, error) {
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	blocks := make([]*cb.Block, len(files))
 
 	for i := range files {
-		blocks[i], err = computeBlockFromFile(dir + channelName + "_" + strconv.Itoa(i) + ".block")
+		blocks[i], err = computeBlockFromFile(blockFile(dir, channelName, i))
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 	}
 
-	return blocks
+	return blocks, nil
+}
+
+// blockFile returns the path of the file, which contains the block with the given number
+func blockFile(dir string, channelName string, number int) string {
+	return filepath.Join(dir, channelName+"_"+strconv.Itoa(number)+".block")
 }
 
 func computeBlockFromFile(filePath string) (*cb.Block, error) {
 	blockData, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		println("ERROR: Unable to read file: " + filePath)
-		return nil, err
+		return nil, fmt.Errorf("Unable to read file: %s", err)
 	}
 
 	block := new(cb.Block)
 	err = proto.Unmarshal(blockData, block)
 	if err != nil {
-		println("ERROR: Unable to parse block: " + filePath)
+		return nil, fmt.Errorf("Unable to parse block %s: %s", filePath, err)
 	}
 
 	return block, nil
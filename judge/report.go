@@ -0,0 +1,106 @@
+package judge
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/evidence"
+	"github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// LedgerSource is the directory, which contains the block files of a single peer
+type LedgerSource struct {
+	Identity string
+	BlockDir string
+}
+
+// Job contains the ledgers and parameters of a single judge run
+type Job struct {
+	Ledgers         []*LedgerSource
+	Options         *backend.Options
+	EvidenceDir     string
+	Redact          bool
+	SigningKey      string
+	CertificatePath string
+	Recipients      []string
+	// CheckpointDir contains the checkpoints of previous runs. If it is set, the run resumes from them and writes new ones.
+	// The checkpoints are not signed, thus the directory must only be writable by the judge
+	CheckpointDir string
+	// Progress receives the messages describing the progress of the run. If it is nil, the run is silent
+	Progress func(message string)
+}
+
+func (j *Job) progress(message string) {
+	if j.Progress != nil {
+		j.Progress(message)
+	}
+}
+
+// Report is the structured result of a judge run
+type Report struct {
+	JudgeVersion  string           `json:"judge_version"`
+	CreatedAt     string           `json:"created_at"`
+	ConsensusType string           `json:"consensus_type"`
+	ChannelName   string           `json:"channel_name"`
+	Ledgers       []*ReportLedger  `json:"ledgers"`
+	Phases        []*ReportPhase   `json:"phases"`
+	Consistent    bool             `json:"consistent"`
+	Check         string           `json:"check,omitempty"`
+	Verdicts      []*ReportVerdict `json:"verdicts"`
+	Certificate   string           `json:"certificate,omitempty"`
+
+	verdict []*verdicts.Verdict
+}
+
+// ReportLedger is a single input ledger of the run
+type ReportLedger struct {
+	Identity string `json:"identity"`
+	Height   int    `json:"height"`
+}
+
+// ReportPhase is a single phase, which was run by the judge
+type ReportPhase struct {
+	Check       string `json:"check"`
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+}
+
+// ReportVerdict is a verdict of the run together with the evidence bundle, which proves it
+type ReportVerdict struct {
+	*evidence.CertifiedVerdict
+	Evaluation string `json:"evaluation"`
+	Bundle     string `json:"bundle,omitempty"`
+}
+
+func newReport(consensusType string, options *backend.Options, ledgers []*backend.Ledger) *Report {
+	report := &Report{
+		JudgeVersion:  Version,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ConsensusType: consensusType,
+		ChannelName:   options.ChannelName,
+		Ledgers:       make([]*ReportLedger, 0, len(ledgers)),
+		Phases:        make([]*ReportPhase, 0),
+		Consistent:    true,
+		Verdicts:      make([]*ReportVerdict, 0),
+	}
+	for _, ledger := range ledgers {
+		report.Ledgers = append(report.Ledgers, &ReportLedger{Identity: ledger.Identity, Height: len(ledger.Blocks)})
+	}
+	return report
+}
+
+func (r *Report) addPhase(phase *backend.Phase, passed bool) {
+	r.Phases = append(r.Phases, &ReportPhase{Check: phase.Check, Description: phase.Description, Passed: passed})
+}
+
+func (r *Report) addVerdicts(check string, verdict []*verdicts.Verdict) {
+	r.Consistent = false
+	r.Check = check
+	r.verdict = verdict
+	for _, v := range verdict {
+		r.Verdicts = append(r.Verdicts, &ReportVerdict{
+			CertifiedVerdict: evidence.NewCertifiedVerdict(check, v),
+			Evaluation:       v.EvaluateVerdict(),
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/evidence"
+)
+
+// Watcher judges the ledgers of a job continuously while they grow. It keeps the parsed blocks of every ledger and the state
+// of the checks between two rounds, thus it only reads the blocks, which were appended since the last poll, and only judges
+// the blocks, which all peers have received since the last round. Peers progressing at different speeds are compared on their
+// common blocks, and the blocks of a peer running ahead are judged as soon as the others have caught up
+type Watcher struct {
+	job           *Job
+	options       *backend.Options
+	feeds         []ledgerFeed
+	ledgers       []*backend.Ledger
+	consensusType string
+	judgedHeight  int
+	// states are the states of the checks after the first stateHeight blocks, which were judged without verdict
+	states      []*backend.CheckpointState
+	stateHeight int
+	rendered    map[string]bool
+	// Verdict receives every verdict once, as soon as it is rendered
+	Verdict func(verdict *ReportVerdict)
+}
+
+// NewWatcher creates a watcher for the ledgers of the job. A ledger is either a directory of block files,
+// or the blockfile storage of a peer. The signing key and certificate of the job are not used
+func NewWatcher(job *Job) (*Watcher, error) {
+	if len(job.Ledgers) < 2 {
+		return nil, fmt.Errorf("The judge needs the ledgers of at least two peers, but %d were given", len(job.Ledgers))
+	}
+	// the options are completed by the genesis config of the channel, thus they are copied
+	options := *job.Options
+	w := &Watcher{
+		job:      job,
+		options:  &options,
+		feeds:    make([]ledgerFeed, 0, len(job.Ledgers)),
+		ledgers:  make([]*backend.Ledger, 0, len(job.Ledgers)),
+		rendered: make(map[string]bool),
+	}
+	for _, source := range job.Ledgers {
+		feed, err := newLedgerFeed(source.BlockDir, job.Options.ChannelName)
+		if err != nil {
+			return nil, err
+		}
+		w.feeds = append(w.feeds, feed)
+		w.ledgers = append(w.ledgers, &backend.Ledger{Identity: source.Identity, Blocks: nil})
+	}
+	return w, nil
+}
+
+// Run polls the ledgers in the given interval until the context is done
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, err := w.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reads the new blocks of all ledgers and judges them, if all peers have reached a new height.
+// It returns the verdicts, which were rendered for the first time
+func (w *Watcher) Poll(ctx context.Context) ([]*ReportVerdict, error) {
+	for i, feed := range w.feeds {
+		blocks, err := feed.next()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read the ledger of %s: %s", w.ledgers[i].Identity, err)
+		}
+		w.ledgers[i].Blocks = append(w.ledgers[i].Blocks, blocks...)
+	}
+
+	height := len(w.ledgers[0].Blocks)
+	for _, ledger := range w.ledgers {
+		if len(ledger.Blocks) < height {
+			height = len(ledger.Blocks)
+		}
+	}
+	if height <= w.judgedHeight {
+		return nil, nil
+	}
+
+	if w.consensusType == "" {
+		consensusType, err := getConsensusType(w.ledgers[0].Blocks)
+		if err != nil {
+			return nil, err
+		}
+		if consensusType == "kafka" {
+			err = w.options.FillBatchSize(w.ledgers[0].Blocks[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+		w.consensusType = consensusType
+	}
+
+	// The checks carry their state through the complete ledger, e.g. the Kafka offsets and the config in effect.
+	// If the backend supports checkpoints, their state after the last round without verdict is carried over to this round,
+	// such that only the common blocks appended since then are judged. Otherwise, all common blocks are judged again
+	common := make([]*backend.Ledger, 0, len(w.ledgers))
+	for _, ledger := range w.ledgers {
+		common = append(common, &backend.Ledger{Identity: ledger.Identity, Blocks: ledger.Blocks[:height]})
+	}
+	ordering, err := backend.NewBackend(w.consensusType, w.options)
+	if err != nil {
+		return nil, err
+	}
+	resumable, isResumable := ordering.(backend.ResumableBackend)
+	var judged []*backend.Ledger
+	if isResumable && w.states != nil {
+		var from uint64
+		judged, from, err = resumeStates(resumable, common, uint64(w.stateHeight), w.states)
+		if err != nil {
+			w.job.progress("Unable to continue with the state of the previous round, judging all blocks: " + err.Error())
+			judged = nil
+		} else {
+			w.job.progress(fmt.Sprintf("Continuing with the state of the first %d blocks, judging the blocks starting with block %d", w.stateHeight, from))
+		}
+	}
+	if judged == nil {
+		err = ordering.DecodeMetadata(common)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The phases of a round are not reported individually, only the verdicts are
+	round := *w.job
+	round.Progress = nil
+	report := newReport(w.consensusType, w.options, common)
+	err = runPhases(ctx, ordering.EvidencePhases(), &round, nil, report)
+	if err == nil && report.verdict == nil {
+		err = runPhases(ctx, ordering.CuttingPhases(), &round, nil, report)
+	}
+	if err != nil {
+		return nil, err
+	}
+	w.judgedHeight = height
+	if isResumable && report.verdict == nil {
+		states := make([]*backend.CheckpointState, 0, len(common))
+		for i := range common {
+			state, err := resumable.CheckpointState(i, uint64(height))
+			if err != nil {
+				return nil, err
+			}
+			states = append(states, state)
+		}
+		w.states, w.stateHeight = states, height
+	}
+	w.job.progress("Judged the first " + strconv.Itoa(height) + " blocks of all ledgers")
+	for _, ledger := range w.ledgers {
+		if len(ledger.Blocks) > height {
+			w.job.progress(ledger.Identity + " is ahead with " + strconv.Itoa(len(ledger.Blocks)-height) + " blocks, which are judged once all peers have received them")
+		}
+	}
+
+	fresh := make([]*ReportVerdict, 0)
+	var exporter *evidence.Exporter
+	for i, v := range report.verdict {
+		key := report.Check + "\x00" + v.Identity() + "\x00" + v.Message()
+		if w.rendered[key] {
+			continue
+		}
+
+		// Bundles are grouped by the height, at which their verdict was rendered
+		if w.job.EvidenceDir != "" {
+			if exporter == nil {
+				exporter, err = evidence.NewExporter(filepath.Join(w.job.EvidenceDir, fmt.Sprintf("height-%06d", height)), w.consensusType, w.options, common, w.job.Redact)
+				if err != nil {
+					return nil, err
+				}
+			}
+			bundleDir, err := exporter.Export(report.Check, v)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to export evidence bundle: %s", err)
+			}
+			report.Verdicts[i].Bundle, err = sealEvidence(bundleDir, w.job.Recipients)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// the verdict is only marked as rendered once its evidence was exported, such that a failed export is retried by the next poll
+		w.rendered[key] = true
+		fresh = append(fresh, report.Verdicts[i])
+		if w.Verdict != nil {
+			w.Verdict(report.Verdicts[i])
+		}
+	}
+	return fresh, nil
+}
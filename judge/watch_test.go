@@ -0,0 +1,164 @@
+package judge
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+// newTestWatcher watches the ledgers in the given directories and records the progress of its rounds
+func newTestWatcher(t *testing.T, f *ledgertest.KafkaFixture, progress *[]string, blockDirs ...string) *Watcher {
+	job := &Job{
+		Options: f.Options(),
+		Progress: func(message string) {
+			*progress = append(*progress, message)
+		},
+	}
+	for i, blockDir := range blockDirs {
+		job.Ledgers = append(job.Ledgers, &LedgerSource{Identity: "peer" + string(rune('A'+i)), BlockDir: blockDir})
+	}
+	w, err := NewWatcher(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// poll runs a round of the watcher and returns the messages of the fresh verdicts together with the progress of the round
+func poll(t *testing.T, w *Watcher, progress *[]string) ([]string, string) {
+	*progress = nil
+	fresh, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]string, 0, len(fresh))
+	for _, v := range fresh {
+		messages = append(messages, v.Message)
+	}
+	return messages, strings.Join(*progress, "\n")
+}
+
+func TestWatcherJudgesAppendedBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}, {{Offset: 5}, {Offset: 6}}, {{Offset: 7}, {Offset: 8}}})
+	dirA, dirB := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	ledgertest.WriteLedger(t, dirA, blocks[:3])
+	ledgertest.WriteLedger(t, dirB, blocks[:3])
+
+	var progress []string
+	w := newTestWatcher(t, f, &progress, dirA, dirB)
+	verdicts, rounds := poll(t, w, &progress)
+	if len(verdicts) != 0 || !strings.Contains(rounds, "Judged the first 3 blocks of all ledgers") {
+		t.Fatalf("Expected the first blocks to be judged without verdict, got %v:\n%s", verdicts, rounds)
+	}
+
+	// peerA runs ahead, thus only the blocks received by both peers are judged
+	ledgertest.WriteLedger(t, dirA, blocks)
+	ledgertest.WriteLedger(t, dirB, blocks[:4])
+	verdicts, rounds = poll(t, w, &progress)
+	if len(verdicts) != 0 || !strings.Contains(rounds, "Continuing with the state of the first 3 blocks, judging the blocks starting with block 3") ||
+		!strings.Contains(rounds, "Judged the first 4 blocks of all ledgers") || !strings.Contains(rounds, "peerA is ahead with 2 blocks") {
+		t.Fatalf("Expected the common block appended since the last round to be judged, got %v:\n%s", verdicts, rounds)
+	}
+
+	verdicts, rounds = poll(t, w, &progress)
+	if len(verdicts) != 0 || rounds != "" {
+		t.Fatalf("Expected no round as long as peerB does not receive new blocks, got %v:\n%s", verdicts, rounds)
+	}
+
+	ledgertest.WriteLedger(t, dirB, blocks)
+	verdicts, rounds = poll(t, w, &progress)
+	if len(verdicts) != 0 || !strings.Contains(rounds, "Continuing with the state of the first 4 blocks") || !strings.Contains(rounds, "Judged the first 6 blocks of all ledgers") {
+		t.Fatalf("Expected the blocks to be judged once peerB caught up, got %v:\n%s", verdicts, rounds)
+	}
+}
+
+func TestWatcherRendersVerdictOnAppendedBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	dirA, dirB := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	ledgertest.WriteLedger(t, dirA, blocks)
+	ledgertest.WriteLedger(t, dirB, blocks)
+
+	var progress []string
+	w := newTestWatcher(t, f, &progress, dirA, dirB)
+	verdicts, rounds := poll(t, w, &progress)
+	if len(verdicts) != 0 {
+		t.Fatalf("Expected the consistent ledgers to pass, got %v:\n%s", verdicts, rounds)
+	}
+
+	// the peers receive different messages at the same offset, which are both signed by the Kafka Cluster
+	blockA := f.Block(blocks[2], []ledgertest.Message{{Offset: 4}, {Offset: 5}}, 0, 0)
+	blockB := f.Block(blocks[2], []ledgertest.Message{{Offset: 4, Data: "other"}, {Offset: 5}}, 0, 0)
+	ledgertest.WriteLedger(t, dirA, []*cb.Block{blockA})
+	ledgertest.WriteLedger(t, dirB, []*cb.Block{blockB})
+	verdicts, rounds = poll(t, w, &progress)
+	if len(verdicts) == 0 || !strings.Contains(rounds, "Continuing with the state of the first 3 blocks") {
+		t.Fatalf("Expected a verdict on the diverging block appended since the last round, got:\n%s", rounds)
+	}
+
+	// the state is not advanced beyond the diverging block, thus the next round judges it again, but does not render its verdict twice
+	ledgertest.WriteLedger(t, dirA, []*cb.Block{f.Block(blockA, []ledgertest.Message{{Offset: 6}, {Offset: 7}}, 0, 0)})
+	ledgertest.WriteLedger(t, dirB, []*cb.Block{f.Block(blockB, []ledgertest.Message{{Offset: 6}, {Offset: 7}}, 0, 0)})
+	verdicts, rounds = poll(t, w, &progress)
+	if len(verdicts) != 0 || !strings.Contains(rounds, "Continuing with the state of the first 3 blocks") || !strings.Contains(rounds, "Judged the first 5 blocks of all ledgers") {
+		t.Fatalf("Expected the verdict not to be rendered twice, got %v:\n%s", verdicts, rounds)
+	}
+}
+
+func TestBlockDirFeedWaitsForMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+	ledgertest.WriteLedger(t, dir, blocks[:1])
+
+	// the metadata of block 1 is not written yet
+	partial := proto.Clone(blocks[1]).(*cb.Block)
+	partial.Metadata.Metadata = partial.Metadata.Metadata[:2]
+	ledgertest.WriteLedger(t, dir, []*cb.Block{partial})
+
+	feed, err := newLedgerFeed(dir, ledgertest.ChannelName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := feed.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 1 {
+		t.Fatalf("Expected only the genesis block to be read, got %d blocks", len(read))
+	}
+
+	ledgertest.WriteLedger(t, dir, blocks[1:])
+	read, err = feed.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 1 || !proto.Equal(read[0], blocks[1]) {
+		t.Fatalf("Expected block 1 to be read once it is complete, got %d blocks", len(read))
+	}
+}
@@ -1,34 +1,449 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/evidence"
 	"github.com/hyperledger/fabric_judge/judge"
+	pb "github.com/hyperledger/fabric_judge/protos/judge"
+	"github.com/hyperledger/fabric_judge/server"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	// fabric_judge verify-evidence <bundle> [kafkaPublicKey] re-checks a single evidence bundle instead of complete ledgers
+	if len(os.Args) > 1 && os.Args[1] == "verify-evidence" {
+		verifyEvidence(os.Args[2:])
+		return
+	}
+	// fabric_judge verify-certificate <certificate> <judgePublicKey> verifies a signed verdict certificate
+	if len(os.Args) > 1 && os.Args[1] == "verify-certificate" {
+		verifyCertificate(os.Args[2:])
+		return
+	}
+	// fabric_judge serve [flags] runs the judge as HTTP service, which accepts jobs and serves their reports and evidence.
+	// With --grpc-listen, cases can also be submitted via gRPC, streaming the ledgers block by block
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serve(os.Args[2:])
+		return
+	}
+	// fabric_judge watch [flags] <channelName> <identity>=<ledger>... judges the ledgers continuously while they grow
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		watch(os.Args[2:])
+		return
+	}
+	// fabric_judge encrypt <bundle or certificate> <recipientPublicKey>... encrypts evidence to the given recipients
+	if len(os.Args) > 1 && os.Args[1] == "encrypt" {
+		encryptEvidence(os.Args[2:])
+		return
+	}
+	// fabric_judge decrypt <container> <recipientSecretKey> <output> decrypts an encrypted bundle or certificate
+	if len(os.Args) > 1 && os.Args[1] == "decrypt" {
+		decryptEvidence(os.Args[2:])
+		return
+	}
+
 	// input arguments:
 	// blockDir1 string, blockDir2 string, identity1 string, identity2 string, channelName string, kafkaPublicKey string, maxBatchSize int, preferredBlockSize int
-	args := os.Args[1:]
+	// optional: absoluteMaxBytes int, ordererVersion string, batchTimeout duration, timingTolerance duration
+	// on etcdraft channels, the BatchSize is read from the channel config and the Kafka specific arguments are ignored
+	// flags: --evidence-dir dir writes an evidence bundle for every verdict into dir
+	// --redact removes the payload data of the envelopes from the bundles of verdicts, which only depend on Merkle leaves, TTC messages and block headers
+	// --sign-key key signs the verdicts with the Ed25519 secret key of the judge and writes them into the file given by --certificate
+	// --encrypt-to key1,key2 encrypts the evidence bundles and the certificate to the recipients with the given box public keys
+	// --checkpoint-dir dir resumes from the checkpoints of the previous run and records the verified blocks for the next one
+	evidenceDir := flag.String("evidence-dir", "", "directory, into which an evidence bundle is written for every verdict")
+	redact := flag.Bool("redact", false, "remove the payload data of the envelopes from all evidence bundles, whose verdict does not depend on it")
+	signingKey := flag.String("sign-key", "", "file containing the raw Ed25519 secret key of the judge, which signs the verdict certificate")
+	certificatePath := flag.String("certificate", "verdict_certificate.json", "file, into which the signed verdict certificate is written")
+	encryptTo := flag.String("encrypt-to", "", "comma separated files containing the raw box public keys of the recipients, to which bundles and certificate are encrypted")
+	checkpointDir := flag.String("checkpoint-dir", "", "directory containing a checkpoint per channel and peer, from which the run resumes and which it updates")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: fabric_judge [flags] <blockDir1> <blockDir2> <identity1> <identity2> <channelName> <kafkaPublicKey> <maxBatchSize> <preferredMaxBytes> [absoluteMaxBytes] [ordererVersion] [batchTimeout] [timingTolerance]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if flag.NArg() < 8 || flag.NArg() > 12 {
+		usage("Expected between 8 and 12 arguments, got " + strconv.Itoa(flag.NArg()))
+	}
+	// flag stops parsing at the first positional argument, thus flags given after them would be taken as arguments
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			usage("Flag " + arg + " must be given before the arguments")
+		}
+	}
+
+	var recipients []string
+	if *encryptTo != "" {
+		recipients = strings.Split(*encryptTo, ",")
+	}
 
 	maxBatchSize, err := strconv.Atoi(args[6])
 	if err != nil {
-		log.Panicf("Invalid maxBatchSize")
+		usage("Invalid maxBatchSize " + args[6])
 	}
 
 	preferredMaxBytes, err := strconv.Atoi(args[7])
 	if err != nil {
-		log.Panicf("Invalid maxBatchSize")
+		usage("Invalid preferredMaxBytes " + args[7])
+	}
+
+	absoluteMaxBytes := 0
+	if len(args) > 8 {
+		absoluteMaxBytes, err = strconv.Atoi(args[8])
+		if err != nil {
+			usage("Invalid absoluteMaxBytes " + args[8])
+		}
+	}
+
+	ordererVersion := validator.DefaultOrdererVersion
+	if len(args) > 9 {
+		ordererVersion = args[9]
+	}
+
+	var batchTimeout time.Duration
+	if len(args) > 10 {
+		batchTimeout, err = time.ParseDuration(args[10])
+		if err != nil {
+			usage("Invalid batchTimeout " + args[10])
+		}
+	}
+
+	timingTolerance := 500 * time.Millisecond
+	if len(args) > 11 {
+		timingTolerance, err = time.ParseDuration(args[11])
+		if err != nil {
+			usage("Invalid timingTolerance " + args[11])
+		}
+	}
+
+	job := &judge.Job{
+		Ledgers: []*judge.LedgerSource{
+			{Identity: args[2], BlockDir: args[0] + "/"},
+			{Identity: args[3], BlockDir: args[1] + "/"},
+		},
+		Options: &backend.Options{
+			ChannelName:       args[4],
+			KafkaPublicKey:    args[5],
+			MaxBatchSize:      maxBatchSize,
+			PreferredMaxBytes: preferredMaxBytes,
+			AbsoluteMaxBytes:  absoluteMaxBytes,
+			OrdererVersion:    ordererVersion,
+			BatchTimeout:      batchTimeout,
+			TimingTolerance:   timingTolerance,
+		},
+		EvidenceDir:     *evidenceDir,
+		Redact:          *redact,
+		SigningKey:      *signingKey,
+		CertificatePath: *certificatePath,
+		Recipients:      recipients,
+		CheckpointDir:   *checkpointDir,
+		Progress: func(message string) {
+			println(message)
+		},
+	}
+	judgeLedgers(job)
+}
+
+// usage prints the error and the usage of the judge and exits
+func usage(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	flag.Usage()
+	os.Exit(2)
+}
+
+// judgeLedgers runs the judge on the ledgers of the job and exits with an error, if it rendered a verdict
+func judgeLedgers(job *judge.Job) {
+	report, err := judge.Run(context.Background(), job)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !report.Consistent {
+		for _, verdict := range report.Verdicts {
+			log.Println(verdict.Evaluation)
+		}
+		log.Fatal("Inconsistency in blocks is ascertained, exiting...")
 	}
+}
 
-	judge.VerifyConsistency(args[0]+"/", args[1]+"/", args[2], args[3], args[4], args[5], maxBatchSize, preferredMaxBytes)
+// verifyEvidence re-checks the verdict of an evidence bundle. The Kafka public key is only needed for bundles of Kafka channels
+func verifyEvidence(args []string) {
+	if len(args) < 1 {
+		log.Fatal("Usage: fabric_judge verify-evidence <bundle> [kafkaPublicKey]")
+	}
+	kafkaPublicKey := ""
+	if len(args) > 1 {
+		kafkaPublicKey = args[1]
+	}
+
+	bundle, err := evidence.ReadBundle(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if bundle.Manifest.ConsensusType == "kafka" && kafkaPublicKey == "" {
+		log.Fatal("The bundle was created for a Kafka channel, thus the Kafka public key is required")
+	}
+
+	println("Re-checking verdict: " + bundle.Manifest.Verdict.Message + " (" + bundle.Manifest.Verdict.Identity + ")")
+	result, err := evidence.VerifyBundle(bundle, kafkaPublicKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	println(result.Outcome.String())
+	for _, reason := range result.Reasons {
+		println("\t" + reason)
+	}
+	if result.Outcome != evidence.Confirmed {
+		os.Exit(1)
+	}
+}
+
+// verifyCertificate checks the signature of a verdict certificate with the trusted public key of the judge and prints the certified verdicts
+func verifyCertificate(args []string) {
+	if len(args) < 2 {
+		log.Fatal("Usage: fabric_judge verify-certificate <certificate> <judgePublicKey>")
+	}
+
+	signed, err := evidence.ReadCertificate(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	certificate, err := signed.Verify(args[1])
+	if err != nil {
+		println("INVALID")
+		println("\t" + err.Error())
+		os.Exit(1)
+	}
+
+	println("VALID")
+	println("\tSigned by " + signed.JudgeKeyID + " (judge version " + certificate.JudgeVersion + ") at " + certificate.CreatedAt)
+	println("\tChannel " + certificate.ChannelName + ", ordered by " + certificate.ConsensusType)
+	for _, ledger := range certificate.Ledgers {
+		println("\tLedger of " + ledger.Identity + ": " + strconv.Itoa(ledger.Height) + " blocks, sha256 " + ledger.SHA256)
+	}
+	if len(certificate.Verdicts) == 0 {
+		println("\tNo inconsistency was ascertained")
+	}
+	for _, verdict := range certificate.Verdicts {
+		println("\t[" + verdict.Check + "] " + verdict.Identity + ": " + verdict.Message)
+	}
+}
+
+// serve runs the HTTP service and optionally the gRPC service until it is interrupted
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := flags.String("listen", "localhost:8080", "address, on which the service listens")
+	workDir := flags.String("work-dir", "judge_jobs", "directory, into which the uploaded ledgers and the evidence of every job are written")
+	workers := flags.Int("workers", 2, "number of jobs, which are run concurrently")
+	queueSize := flags.Int("queue", 16, "number of jobs, which can wait for a worker")
+	keyDir := flags.String("key-dir", "", "directory containing the raw Kafka and recipient public keys, which jobs reference by their key id")
+	signingKey := flags.String("sign-key", "", "file containing the raw Ed25519 secret key of the judge, which signs the verdict certificate of every job")
+	ledgerRoot := flags.String("ledger-root", "", "directory, below which jobs may reference ledgers by their path instead of uploading them")
+	maxUpload := flags.Int64("max-upload", 1<<30, "maximum size of a job submission in bytes")
+	maxArchive := flags.Int64("max-archive", 4<<30, "maximum size of a single ledger archive after its decompression in bytes")
+	grpcListen := flags.String("grpc-listen", "", "address, on which the gRPC service listens. If it is empty, only the HTTP service is run")
+	checkpointDir := flags.String("checkpoint-dir", "", "directory containing the checkpoints of jobs on ledgers below the ledger root, from which they resume and which they update")
+	flags.Parse(args)
+
+	service, err := server.NewServer(&server.Config{
+		WorkDir:         *workDir,
+		Workers:         *workers,
+		QueueSize:       *queueSize,
+		KeyDir:          *keyDir,
+		SigningKey:      *signingKey,
+		LedgerRoot:      *ledgerRoot,
+		MaxUploadBytes:  *maxUpload,
+		MaxArchiveBytes: *maxArchive,
+		CheckpointDir:   *checkpointDir,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var grpcServer *grpc.Server
+	if *grpcListen != "" {
+		listener, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(server.MaxGrpcMessageBytes), grpc.MaxSendMsgSize(server.MaxGrpcMessageBytes))
+		pb.RegisterJudgeServer(grpcServer, server.NewJudgeService(service))
+		println("Judge gRPC service listening on " + *grpcListen)
+		go func() {
+			err := grpcServer.Serve(listener)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	httpServer := &http.Server{Addr: *listen, Handler: service.Handler()}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		println("Shutting down, running jobs are cancelled")
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		httpServer.Shutdown(context.Background())
+	}()
+
+	println("Judge service listening on " + *listen)
+	err = httpServer.ListenAndServe()
+	service.Close()
+	if err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+// watch follows the ledgers of the peers and prints every verdict as soon as it is rendered, until it is interrupted.
+// A ledger is either a directory of block files or the blockfile storage of a peer
+func watch(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := flags.Duration("interval", 10*time.Second, "interval, in which the ledgers are polled for new blocks")
+	kafkaPublicKey := flags.String("kafka-key", "", "file containing the raw public key of the Kafka Cluster, required on Kafka channels")
+	maxBatchSize := flags.Int("max-batch-size", 0, "MaxMessageCount of the BatchSize on Kafka channels. If it is not given, it is read from the genesis config")
+	preferredMaxBytes := flags.Int("preferred-max-bytes", 0, "PreferredMaxBytes of the BatchSize on Kafka channels. If it is not given, it is read from the genesis config")
+	absoluteMaxBytes := flags.Int("absolute-max-bytes", 0, "AbsoluteMaxBytes of the BatchSize on Kafka channels. If it is not given, it is read from the genesis config")
+	ordererVersion := flags.String("orderer-version", validator.DefaultOrdererVersion, "version of the orderers, which determines the byte accounting of the blockcutter")
+	batchTimeout := flags.Duration("batch-timeout", 0, "BatchTimeout of the channel, if the timing of the blocks is verified")
+	timingTolerance := flags.Duration("timing-tolerance", 500*time.Millisecond, "tolerance of the verification of the BatchTimeout")
+	evidenceDir := flags.String("evidence-dir", "", "directory, into which an evidence bundle is written for every verdict")
+	redact := flags.Bool("redact", false, "remove the payload data of the envelopes from all evidence bundles, whose verdict does not depend on it")
+	encryptTo := flags.String("encrypt-to", "", "comma separated files containing the raw box public keys of the recipients, to which bundles are encrypted")
+	flags.Parse(args)
+	args = flags.Args()
+	if len(args) < 3 {
+		log.Fatal("Usage: fabric_judge watch [flags] <channelName> <identity>=<ledger> <identity>=<ledger>...")
+	}
+
+	job := &judge.Job{
+		Options: &backend.Options{
+			ChannelName:       args[0],
+			KafkaPublicKey:    *kafkaPublicKey,
+			MaxBatchSize:      *maxBatchSize,
+			PreferredMaxBytes: *preferredMaxBytes,
+			AbsoluteMaxBytes:  *absoluteMaxBytes,
+			OrdererVersion:    *ordererVersion,
+			BatchTimeout:      *batchTimeout,
+			TimingTolerance:   *timingTolerance,
+		},
+		EvidenceDir: *evidenceDir,
+		Redact:      *redact,
+		Progress: func(message string) {
+			println(message)
+		},
+	}
+	if *encryptTo != "" {
+		job.Recipients = strings.Split(*encryptTo, ",")
+	}
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			log.Fatalf("Invalid ledger %s, expected <identity>=<ledger>", arg)
+		}
+		job.Ledgers = append(job.Ledgers, &judge.LedgerSource{Identity: parts[0], BlockDir: parts[1]})
+	}
+
+	watcher, err := judge.NewWatcher(job)
+	if err != nil {
+		log.Fatal(err)
+	}
+	watcher.Verdict = func(verdict *judge.ReportVerdict) {
+		log.Println(verdict.Evaluation)
+		if verdict.Bundle != "" {
+			println("Evidence bundle written to " + verdict.Bundle)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	println("Watching the ledgers of channel " + args[0])
+	err = watcher.Run(ctx, *interval)
+	if err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
+
+// encryptEvidence encrypts an evidence bundle or a certificate to the recipients with the given box public keys
+func encryptEvidence(args []string) {
+	if len(args) < 2 {
+		log.Fatal("Usage: fabric_judge encrypt <bundle or certificate> <recipientPublicKey>...")
+	}
+
+	container, err := evidence.Encrypt(args[0], args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	sealedPath := strings.TrimSuffix(args[0], "/") + evidence.SealedExtension
+	err = container.Write(sealedPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	println("Encrypted evidence written to " + sealedPath)
+}
+
+// decryptEvidence decrypts an encrypted bundle or certificate with the secret key of a recipient and writes it to the output path
+func decryptEvidence(args []string) {
+	if len(args) < 3 {
+		log.Fatal("Usage: fabric_judge decrypt <container> <recipientSecretKey> <output>")
+	}
+
+	container, err := evidence.ReadSealedContainer(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	header, plaintext, err := container.Decrypt(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	println("Decrypted " + header.Content + " " + header.Name + ", created at " + header.CreatedAt + ", sha256 " + header.SHA256)
+	for _, recipient := range header.Recipients {
+		println("\tRecipient " + recipient.KeyID)
+	}
+
+	err = evidence.Extract(header, plaintext, args[2])
+	if err != nil {
+		log.Fatal(err)
+	}
+	println("Decrypted evidence written to " + args[2])
 }
 
 // func main() {
-// 	dir1 := "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/scripts/judge/data/peer0.org1.example.com_blocks/blocks/"
-// 	dir2 := "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/scripts/judge/data/peer1.org1.example.com_blocks/blocks/"
-// 	pkPath := "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/KafkaKeyPair/public.key"
-// 	judge.VerifyConsistency(dir1, dir2, "peer0.org1", "peer1.org1", "mychannel", pkPath, 10, 512000)
+// 	judgeLedgers(&judge.Job{
+// 		Ledgers: []*judge.LedgerSource{
+// 			{Identity: "peer0.org1", BlockDir: "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/scripts/judge/data/peer0.org1.example.com_blocks/blocks/"},
+// 			{Identity: "peer1.org1", BlockDir: "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/scripts/judge/data/peer1.org1.example.com_blocks/blocks/"},
+// 		},
+// 		Options: &backend.Options{
+// 			ChannelName:       "mychannel",
+// 			KafkaPublicKey:    "/home/simon/go/src/github.com/hyperledger/fabric-samples/first-network/KafkaKeyPair/public.key",
+// 			MaxBatchSize:      10,
+// 			PreferredMaxBytes: 512000,
+// 			OrdererVersion:    validator.DefaultOrdererVersion,
+// 		},
+// 	})
 // }
@@ -0,0 +1,68 @@
+package msp
+
+import (
+	"fmt"
+	"sort"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+)
+
+// NewManagerFromConfig creates the OfflineMSPs of all application and orderer organizations of the channel config
+func NewManagerFromConfig(config *cb.Config) (*Manager, error) {
+	msps := make([]*OfflineMSP, 0)
+
+	ordererMSPs := make([]string, 0)
+
+	for _, groupName := range []string{channelconfig.ApplicationGroupKey, channelconfig.OrdererGroupKey} {
+		group, ok := config.ChannelGroup.Groups[groupName]
+		if !ok {
+			continue
+		}
+
+		orgNames := make([]string, 0, len(group.Groups))
+		for orgName := range group.Groups {
+			orgNames = append(orgNames, orgName)
+		}
+		sort.Strings(orgNames)
+
+		for _, orgName := range orgNames {
+			value, ok := group.Groups[orgName].Values["MSP"]
+			if !ok {
+				return nil, fmt.Errorf("organization %s does not contain an MSP config", orgName)
+			}
+
+			mspConfig := &mb.MSPConfig{}
+			err := proto.Unmarshal(value.Value, mspConfig)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MSP config of organization %s: %s", orgName, err)
+			}
+
+			msp, err := NewOfflineMSP(mspConfig)
+			if err != nil {
+				return nil, err
+			}
+			msps = append(msps, msp)
+			if groupName == channelconfig.OrdererGroupKey {
+				ordererMSPs = append(ordererMSPs, msp.Name)
+			}
+		}
+	}
+
+	manager := NewManager(msps)
+	for _, name := range ordererMSPs {
+		manager.ordererMSPs[name] = true
+	}
+	return manager, nil
+}
+
+// NewManagerFromConfigBlock creates the OfflineMSPs of all organizations from the channel config in the given config block
+func NewManagerFromConfigBlock(block *cb.Block) (*Manager, error) {
+	config, err := channelconfig.GetConfigFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagerFromConfig(config)
+}
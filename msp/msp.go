@@ -0,0 +1,221 @@
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+)
+
+// FABRIC is the type of the X.509 based MSP
+const FABRIC = 0
+
+// OfflineMSP verifies identities of a single organization using the root and intermediate CAs of the channel config
+type OfflineMSP struct {
+	Name string
+
+	roots         *x509.CertPool
+	intermediates *x509.CertPool
+	revoked       map[string]bool
+	nodeOUs       *mb.FabricNodeOUs
+}
+
+// Identity is a deserialized creator, whose certificate chain was verified by its MSP
+type Identity struct {
+	MSPID       string
+	Certificate *x509.Certificate
+}
+
+// NewOfflineMSP creates an OfflineMSP from the given MSP config
+func NewOfflineMSP(mspConfig *mb.MSPConfig) (*OfflineMSP, error) {
+	if mspConfig.Type != FABRIC {
+		return nil, fmt.Errorf("unsupported MSP type %d", mspConfig.Type)
+	}
+
+	fabricConfig := &mb.FabricMSPConfig{}
+	err := proto.Unmarshal(mspConfig.Config, fabricConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	msp := &OfflineMSP{
+		Name:          fabricConfig.Name,
+		roots:         x509.NewCertPool(),
+		intermediates: x509.NewCertPool(),
+		revoked:       make(map[string]bool),
+		nodeOUs:       fabricConfig.FabricNodeOus,
+	}
+
+	for _, pemBytes := range fabricConfig.RootCerts {
+		cert, err := parseCertificate(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root certificate of MSP %s: %s", msp.Name, err)
+		}
+		msp.roots.AddCert(cert)
+	}
+	for _, pemBytes := range fabricConfig.IntermediateCerts {
+		cert, err := parseCertificate(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid intermediate certificate of MSP %s: %s", msp.Name, err)
+		}
+		msp.intermediates.AddCert(cert)
+	}
+	for _, pemBytes := range fabricConfig.RevocationList {
+		block, _ := pem.Decode(pemBytes)
+		if block == nil {
+			return nil, fmt.Errorf("invalid revocation list of MSP %s", msp.Name)
+		}
+		crl, err := x509.ParseCRL(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid revocation list of MSP %s: %s", msp.Name, err)
+		}
+		for _, revokedCert := range crl.TBSCertList.RevokedCertificates {
+			msp.revoked[revokedCert.SerialNumber.String()] = true
+		}
+	}
+
+	return msp, nil
+}
+
+// Validate checks the certificate chain of the identity at the given point in time, whether it was revoked and whether it carries one of the configured NodeOUs
+func (msp *OfflineMSP) Validate(cert *x509.Certificate, at time.Time) error {
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         msp.roots,
+		Intermediates: msp.intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("certificate chain of %s is invalid: %s", cert.Subject, err)
+	}
+
+	if msp.revoked[cert.SerialNumber.String()] {
+		return fmt.Errorf("certificate of %s was revoked", cert.Subject)
+	}
+
+	if msp.nodeOUs != nil && msp.nodeOUs.Enable {
+		if !hasNodeOU(cert, msp.nodeOUs) {
+			return fmt.Errorf("certificate of %s does not carry any of the NodeOUs of MSP %s", cert.Subject, msp.Name)
+		}
+	}
+
+	return nil
+}
+
+// Manager contains the OfflineMSPs of all organizations of the channel
+type Manager struct {
+	msps        map[string]*OfflineMSP
+	ordererMSPs map[string]bool
+}
+
+// NewManager creates a Manager for the given MSPs
+func NewManager(msps []*OfflineMSP) *Manager {
+	manager := &Manager{
+		msps:        make(map[string]*OfflineMSP),
+		ordererMSPs: make(map[string]bool),
+	}
+	for _, msp := range msps {
+		manager.msps[msp.Name] = msp
+	}
+	return manager
+}
+
+// IsOrdererMSP returns whether the MSP belongs to an orderer organization of the channel
+func (m *Manager) IsOrdererMSP(mspID string) bool {
+	return m.ordererMSPs[mspID]
+}
+
+// DeserializeIdentity unmarshals the creator of a SignatureHeader and validates it with the MSP of its organization
+func (m *Manager) DeserializeIdentity(creator []byte, at time.Time) (*Identity, error) {
+	serializedIdentity := &mb.SerializedIdentity{}
+	err := proto.Unmarshal(creator, serializedIdentity)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal creator: %s", err)
+	}
+
+	msp, ok := m.msps[serializedIdentity.Mspid]
+	if !ok {
+		return nil, fmt.Errorf("MSP %s is not part of the channel config", serializedIdentity.Mspid)
+	}
+
+	cert, err := parseCertificate(serializedIdentity.IdBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate of creator: %s", err)
+	}
+
+	err = msp.Validate(cert, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{MSPID: serializedIdentity.Mspid, Certificate: cert}, nil
+}
+
+// String returns the MSP and the common name of the identity
+func (id *Identity) String() string {
+	return id.MSPID + "/" + id.Certificate.Subject.CommonName
+}
+
+// Verify checks the ECDSA signature of the identity over the given message.
+// As Fabric does, signatures with a high S value are rejected to prevent malleability
+func (id *Identity) Verify(msg []byte, signature []byte) error {
+	publicKey, ok := id.Certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key of %s is not an ECDSA key", id.Certificate.Subject)
+	}
+
+	sig := struct {
+		R, S *big.Int
+	}{}
+	rest, err := asn1.Unmarshal(signature, &sig)
+	if err != nil || len(rest) != 0 || sig.R == nil || sig.S == nil {
+		return fmt.Errorf("signature is not a valid ECDSA signature")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return fmt.Errorf("signature contains non-positive values")
+	}
+	if !isLowS(publicKey.Curve, sig.S) {
+		return fmt.Errorf("signature has a high S value")
+	}
+
+	digest := sha256.Sum256(msg)
+	if !ecdsa.Verify(publicKey, digest[:], sig.R, sig.S) {
+		return fmt.Errorf("signature of %s is invalid", id.Certificate.Subject)
+	}
+	return nil
+}
+
+func parseCertificate(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func hasNodeOU(cert *x509.Certificate, nodeOUs *mb.FabricNodeOUs) bool {
+	for _, identifier := range []*mb.FabricOUIdentifier{nodeOUs.ClientOuIdentifier, nodeOUs.PeerOuIdentifier, nodeOUs.AdminOuIdentifier, nodeOUs.OrdererOuIdentifier} {
+		if identifier == nil {
+			continue
+		}
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if ou == identifier.OrganizationalUnitIdentifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isLowS(curve elliptic.Curve, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+	return s.Cmp(halfOrder) <= 0
+}
@@ -0,0 +1,179 @@
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	mb "github.com/hyperledger/fabric_judge/protos/msp"
+)
+
+// testOrg is the CA of an organization, which issues the certificates of its members
+type testOrg struct {
+	name   string
+	key    *ecdsa.PrivateKey
+	cert   *x509.Certificate
+	caPEM  []byte
+	serial int64
+}
+
+func newTestOrg(t *testing.T, name string) *testOrg {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca." + name}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testOrg{name: name, key: key, cert: cert, caPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), serial: 1}
+}
+
+// issue returns the key and the PEM encoded certificate of a new member of the organization
+func (o *testOrg) issue(t *testing.T, commonName string) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.serial++
+	template := &x509.Certificate{SerialNumber: big.NewInt(o.serial), Subject: pkix.Name{CommonName: commonName}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, o.cert, &key.PublicKey, o.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func (o *testOrg) group(t *testing.T) *cb.ConfigGroup {
+	fabricConfig, err := proto.Marshal(&mb.FabricMSPConfig{Name: o.name, RootCerts: [][]byte{o.caPEM}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mspConfig, err := proto.Marshal(&mb.MSPConfig{Type: FABRIC, Config: fabricConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cb.ConfigGroup{Values: map[string]*cb.ConfigValue{"MSP": {Value: mspConfig}}}
+}
+
+// newTestManager returns the MSPs of a channel with the application organization Org1MSP and the orderer organization OrdererMSP
+func newTestManager(t *testing.T, org1 *testOrg, orderer *testOrg) *Manager {
+	manager, err := NewManagerFromConfig(&cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{
+		channelconfig.ApplicationGroupKey: {Groups: map[string]*cb.ConfigGroup{"Org1": org1.group(t)}},
+		channelconfig.OrdererGroupKey:     {Groups: map[string]*cb.ConfigGroup{"OrdererOrg": orderer.group(t)}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func serializedIdentity(t *testing.T, mspID string, certPEM []byte) []byte {
+	creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: certPEM})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return creator
+}
+
+// sign returns the ECDSA signature over the message with the given S value, which is either the low or the high one
+func sign(t *testing.T, key *ecdsa.PrivateKey, msg []byte, highS bool) []byte {
+	digest := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isLowS(key.Curve, s) == highS {
+		s = new(big.Int).Sub(key.Curve.Params().N, s)
+	}
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signature
+}
+
+func TestValidCreator(t *testing.T) {
+	org1, orderer := newTestOrg(t, "Org1MSP"), newTestOrg(t, "OrdererMSP")
+	manager := newTestManager(t, org1, orderer)
+	key, certPEM := org1.issue(t, "client.org1")
+
+	identity, err := manager.DeserializeIdentity(serializedIdentity(t, "Org1MSP", certPEM), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.String() != "Org1MSP/client.org1" || manager.IsOrdererMSP(identity.MSPID) || !manager.IsOrdererMSP("OrdererMSP") {
+		t.Fatalf("Expected a client of Org1MSP, got %s", identity)
+	}
+	err = identity.Verify([]byte("payload"), sign(t, key, []byte("payload"), false))
+	if err != nil {
+		t.Fatalf("Expected the signature of the creator to be valid, got: %s", err)
+	}
+	err = identity.Verify([]byte("other payload"), sign(t, key, []byte("payload"), false))
+	if err == nil {
+		t.Fatal("Expected the signature over another payload to be rejected")
+	}
+}
+
+func TestCreatorOfWrongOrg(t *testing.T) {
+	org1, orderer := newTestOrg(t, "Org1MSP"), newTestOrg(t, "OrdererMSP")
+	manager := newTestManager(t, org1, orderer)
+
+	// the certificate of an orderer does not make it a member of Org1MSP
+	_, certPEM := orderer.issue(t, "orderer0")
+	_, err := manager.DeserializeIdentity(serializedIdentity(t, "Org1MSP", certPEM), time.Now())
+	if err == nil {
+		t.Fatal("Expected the certificate issued by another organization to be rejected")
+	}
+
+	// organizations, which are not part of the channel config, are rejected as well
+	foreign := newTestOrg(t, "Org2MSP")
+	_, certPEM = foreign.issue(t, "client.org2")
+	_, err = manager.DeserializeIdentity(serializedIdentity(t, "Org2MSP", certPEM), time.Now())
+	if err == nil {
+		t.Fatal("Expected the creator of an unknown MSP to be rejected")
+	}
+}
+
+func TestCreatorOutsideOfValidity(t *testing.T) {
+	org1, orderer := newTestOrg(t, "Org1MSP"), newTestOrg(t, "OrdererMSP")
+	manager := newTestManager(t, org1, orderer)
+	_, certPEM := org1.issue(t, "client.org1")
+
+	_, err := manager.DeserializeIdentity(serializedIdentity(t, "Org1MSP", certPEM), time.Now().Add(2*time.Hour))
+	if err == nil {
+		t.Fatal("Expected the certificate to be rejected after it expired")
+	}
+}
+
+func TestHighSSignature(t *testing.T) {
+	org1, orderer := newTestOrg(t, "Org1MSP"), newTestOrg(t, "OrdererMSP")
+	manager := newTestManager(t, org1, orderer)
+	key, certPEM := org1.issue(t, "client.org1")
+	identity, err := manager.DeserializeIdentity(serializedIdentity(t, "Org1MSP", certPEM), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the high S signature is mathematically valid, but Fabric rejects it to prevent malleability
+	err = identity.Verify([]byte("payload"), sign(t, key, []byte("payload"), true))
+	if err == nil {
+		t.Fatal("Expected the signature with a high S value to be rejected")
+	}
+}
@@ -0,0 +1,237 @@
+//
+//Copyright IBM Corp. 2016 All Rights Reserved.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: common/policies.proto
+
+package common
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Policy_PolicyType int32
+
+const (
+	Policy_UNKNOWN       Policy_PolicyType = 0 // Reserved to check for proper initialization
+	Policy_SIGNATURE     Policy_PolicyType = 1
+	Policy_MSP           Policy_PolicyType = 2
+	Policy_IMPLICIT_META Policy_PolicyType = 3
+)
+
+// Enum value maps for Policy_PolicyType.
+var (
+	Policy_PolicyType_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "SIGNATURE",
+		2: "MSP",
+		3: "IMPLICIT_META",
+	}
+	Policy_PolicyType_value = map[string]int32{
+		"UNKNOWN":       0,
+		"SIGNATURE":     1,
+		"MSP":           2,
+		"IMPLICIT_META": 3,
+	}
+)
+
+func (x Policy_PolicyType) Enum() *Policy_PolicyType {
+	p := new(Policy_PolicyType)
+	*p = x
+	return p
+}
+
+func (x Policy_PolicyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Policy_PolicyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_policies_proto_enumTypes[0].Descriptor()
+}
+
+func (Policy_PolicyType) Type() protoreflect.EnumType {
+	return &file_common_policies_proto_enumTypes[0]
+}
+
+func (x Policy_PolicyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Policy_PolicyType.Descriptor instead.
+func (Policy_PolicyType) EnumDescriptor() ([]byte, []int) {
+	return file_common_policies_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Policy expresses a policy which the orderer can evaluate, because there has been some desire expressed to support
+// multiple policy engines, this is typed as a oneof for now
+type Policy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  int32  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"` // For outside implementors, consider the first 1000 types reserved, otherwise one of PolicyType
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_policies_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_common_policies_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_common_policies_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Policy) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Policy) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_common_policies_proto protoreflect.FileDescriptor
+
+var file_common_policies_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x22,
+	0x78, 0x0a, 0x06, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x44, 0x0a, 0x0a, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0d,
+	0x0a, 0x09, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x01, 0x12, 0x07, 0x0a,
+	0x03, 0x4d, 0x53, 0x50, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4d, 0x50, 0x4c, 0x49, 0x43,
+	0x49, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x41, 0x10, 0x03, 0x42, 0x59, 0x0a, 0x24, 0x6f, 0x72, 0x67,
+	0x2e, 0x68, 0x79, 0x70, 0x65, 0x72, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x2e, 0x66, 0x61, 0x62,
+	0x72, 0x69, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x79,
+	0x70, 0x65, 0x72, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x2f, 0x66, 0x61, 0x62, 0x72, 0x69, 0x63,
+	0x5f, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_common_policies_proto_rawDescOnce sync.Once
+	file_common_policies_proto_rawDescData = file_common_policies_proto_rawDesc
+)
+
+func file_common_policies_proto_rawDescGZIP() []byte {
+	file_common_policies_proto_rawDescOnce.Do(func() {
+		file_common_policies_proto_rawDescData = protoimpl.X.CompressGZIP(file_common_policies_proto_rawDescData)
+	})
+	return file_common_policies_proto_rawDescData
+}
+
+var file_common_policies_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_common_policies_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_common_policies_proto_goTypes = []interface{}{
+	(Policy_PolicyType)(0), // 0: common.Policy.PolicyType
+	(*Policy)(nil),         // 1: common.Policy
+}
+var file_common_policies_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_common_policies_proto_init() }
+func file_common_policies_proto_init() {
+	if File_common_policies_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_common_policies_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Policy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_common_policies_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_common_policies_proto_goTypes,
+		DependencyIndexes: file_common_policies_proto_depIdxs,
+		EnumInfos:         file_common_policies_proto_enumTypes,
+		MessageInfos:      file_common_policies_proto_msgTypes,
+	}.Build()
+	File_common_policies_proto = out.File
+	file_common_policies_proto_rawDesc = nil
+	file_common_policies_proto_goTypes = nil
+	file_common_policies_proto_depIdxs = nil
+}
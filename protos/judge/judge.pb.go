@@ -0,0 +1,1651 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: judge/judge.proto
+
+package judge
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/hyperledger/fabric_judge/protos/common"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type CaseState int32
+
+const (
+	CaseState_AWAITING_BLOCKS CaseState = 0
+	CaseState_QUEUED          CaseState = 1
+	CaseState_RUNNING         CaseState = 2
+	CaseState_SUCCEEDED       CaseState = 3
+	CaseState_FAILED          CaseState = 4
+	CaseState_CANCELLED       CaseState = 5
+)
+
+// Enum value maps for CaseState.
+var (
+	CaseState_name = map[int32]string{
+		0: "AWAITING_BLOCKS",
+		1: "QUEUED",
+		2: "RUNNING",
+		3: "SUCCEEDED",
+		4: "FAILED",
+		5: "CANCELLED",
+	}
+	CaseState_value = map[string]int32{
+		"AWAITING_BLOCKS": 0,
+		"QUEUED":          1,
+		"RUNNING":         2,
+		"SUCCEEDED":       3,
+		"FAILED":          4,
+		"CANCELLED":       5,
+	}
+)
+
+func (x CaseState) Enum() *CaseState {
+	p := new(CaseState)
+	*p = x
+	return p
+}
+
+func (x CaseState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CaseState) Descriptor() protoreflect.EnumDescriptor {
+	return file_judge_judge_proto_enumTypes[0].Descriptor()
+}
+
+func (CaseState) Type() protoreflect.EnumType {
+	return &file_judge_judge_proto_enumTypes[0]
+}
+
+func (x CaseState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CaseState.Descriptor instead.
+func (CaseState) EnumDescriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{0}
+}
+
+// VerdictType states whether a verdict is rendered against the Kafka Cluster, an orderer, a peer or the ordering service as a whole
+type VerdictType int32
+
+const (
+	VerdictType_KAFKA_CLUSTER    VerdictType = 0
+	VerdictType_ORDERER          VerdictType = 1
+	VerdictType_PEER             VerdictType = 2
+	VerdictType_ORDERING_SERVICE VerdictType = 3
+)
+
+// Enum value maps for VerdictType.
+var (
+	VerdictType_name = map[int32]string{
+		0: "KAFKA_CLUSTER",
+		1: "ORDERER",
+		2: "PEER",
+		3: "ORDERING_SERVICE",
+	}
+	VerdictType_value = map[string]int32{
+		"KAFKA_CLUSTER":    0,
+		"ORDERER":          1,
+		"PEER":             2,
+		"ORDERING_SERVICE": 3,
+	}
+)
+
+func (x VerdictType) Enum() *VerdictType {
+	p := new(VerdictType)
+	*p = x
+	return p
+}
+
+func (x VerdictType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VerdictType) Descriptor() protoreflect.EnumDescriptor {
+	return file_judge_judge_proto_enumTypes[1].Descriptor()
+}
+
+func (VerdictType) Type() protoreflect.EnumType {
+	return &file_judge_judge_proto_enumTypes[1]
+}
+
+func (x VerdictType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VerdictType.Descriptor instead.
+func (VerdictType) EnumDescriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{1}
+}
+
+// CaseRequest contains the parameters of a case, which are not part of the ledgers.
+// Keys are referenced by their key id and resolved in the key directory of the service
+type CaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	// peers are the identities of the peers, whose ledgers are uploaded. At least two ledgers are needed
+	Peers             []string `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	KafkaKeyId        string   `protobuf:"bytes,3,opt,name=kafka_key_id,json=kafkaKeyId,proto3" json:"kafka_key_id,omitempty"`
+	MaxBatchSize      int32    `protobuf:"varint,4,opt,name=max_batch_size,json=maxBatchSize,proto3" json:"max_batch_size,omitempty"`
+	PreferredMaxBytes int32    `protobuf:"varint,5,opt,name=preferred_max_bytes,json=preferredMaxBytes,proto3" json:"preferred_max_bytes,omitempty"`
+	AbsoluteMaxBytes  int32    `protobuf:"varint,6,opt,name=absolute_max_bytes,json=absoluteMaxBytes,proto3" json:"absolute_max_bytes,omitempty"`
+	OrdererVersion    string   `protobuf:"bytes,7,opt,name=orderer_version,json=ordererVersion,proto3" json:"orderer_version,omitempty"`
+	// batch_timeout and timing_tolerance are durations like "2s" or "500ms"
+	BatchTimeout    string   `protobuf:"bytes,8,opt,name=batch_timeout,json=batchTimeout,proto3" json:"batch_timeout,omitempty"`
+	TimingTolerance string   `protobuf:"bytes,9,opt,name=timing_tolerance,json=timingTolerance,proto3" json:"timing_tolerance,omitempty"`
+	Redact          bool     `protobuf:"varint,10,opt,name=redact,proto3" json:"redact,omitempty"`
+	RecipientKeyIds []string `protobuf:"bytes,11,rep,name=recipient_key_ids,json=recipientKeyIds,proto3" json:"recipient_key_ids,omitempty"`
+}
+
+func (x *CaseRequest) Reset() {
+	*x = CaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseRequest) ProtoMessage() {}
+
+func (x *CaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseRequest.ProtoReflect.Descriptor instead.
+func (*CaseRequest) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CaseRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *CaseRequest) GetPeers() []string {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *CaseRequest) GetKafkaKeyId() string {
+	if x != nil {
+		return x.KafkaKeyId
+	}
+	return ""
+}
+
+func (x *CaseRequest) GetMaxBatchSize() int32 {
+	if x != nil {
+		return x.MaxBatchSize
+	}
+	return 0
+}
+
+func (x *CaseRequest) GetPreferredMaxBytes() int32 {
+	if x != nil {
+		return x.PreferredMaxBytes
+	}
+	return 0
+}
+
+func (x *CaseRequest) GetAbsoluteMaxBytes() int32 {
+	if x != nil {
+		return x.AbsoluteMaxBytes
+	}
+	return 0
+}
+
+func (x *CaseRequest) GetOrdererVersion() string {
+	if x != nil {
+		return x.OrdererVersion
+	}
+	return ""
+}
+
+func (x *CaseRequest) GetBatchTimeout() string {
+	if x != nil {
+		return x.BatchTimeout
+	}
+	return ""
+}
+
+func (x *CaseRequest) GetTimingTolerance() string {
+	if x != nil {
+		return x.TimingTolerance
+	}
+	return ""
+}
+
+func (x *CaseRequest) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+func (x *CaseRequest) GetRecipientKeyIds() []string {
+	if x != nil {
+		return x.RecipientKeyIds
+	}
+	return nil
+}
+
+type CaseID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CaseId string `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+}
+
+func (x *CaseID) Reset() {
+	*x = CaseID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CaseID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseID) ProtoMessage() {}
+
+func (x *CaseID) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseID.ProtoReflect.Descriptor instead.
+func (*CaseID) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CaseID) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+type CaseStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CaseId string    `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	State  CaseState `protobuf:"varint,2,opt,name=state,proto3,enum=judge.CaseState" json:"state,omitempty"`
+	Error  string    `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// pending_peers are the peers, whose ledgers have not been uploaded completely
+	PendingPeers []string `protobuf:"bytes,4,rep,name=pending_peers,json=pendingPeers,proto3" json:"pending_peers,omitempty"`
+}
+
+func (x *CaseStatus) Reset() {
+	*x = CaseStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CaseStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseStatus) ProtoMessage() {}
+
+func (x *CaseStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseStatus.ProtoReflect.Descriptor instead.
+func (*CaseStatus) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CaseStatus) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *CaseStatus) GetState() CaseState {
+	if x != nil {
+		return x.State
+	}
+	return CaseState_AWAITING_BLOCKS
+}
+
+func (x *CaseStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CaseStatus) GetPendingPeers() []string {
+	if x != nil {
+		return x.PendingPeers
+	}
+	return nil
+}
+
+type BlockUpload struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// case_id and peer are only read from the first message of the stream
+	CaseId string        `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	Peer   string        `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	Block  *common.Block `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *BlockUpload) Reset() {
+	*x = BlockUpload{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockUpload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUpload) ProtoMessage() {}
+
+func (x *BlockUpload) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUpload.ProtoReflect.Descriptor instead.
+func (*BlockUpload) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlockUpload) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *BlockUpload) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *BlockUpload) GetBlock() *common.Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+type LedgerReceipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CaseId string `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	Peer   string `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	Height uint64 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// sha256 is the digest of the concatenated blocks of the ledger, as recorded in the verdict certificate
+	Sha256 string `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+}
+
+func (x *LedgerReceipt) Reset() {
+	*x = LedgerReceipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LedgerReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerReceipt) ProtoMessage() {}
+
+func (x *LedgerReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerReceipt.ProtoReflect.Descriptor instead.
+func (*LedgerReceipt) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LedgerReceipt) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *LedgerReceipt) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *LedgerReceipt) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *LedgerReceipt) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+// Evidence references the blocks of a single ledger, which are needed to reproduce a verdict
+type Evidence struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ledger string   `protobuf:"bytes,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Blocks []uint64 `protobuf:"varint,2,rep,packed,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *Evidence) Reset() {
+	*x = Evidence{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Evidence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evidence) ProtoMessage() {}
+
+func (x *Evidence) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evidence.ProtoReflect.Descriptor instead.
+func (*Evidence) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Evidence) GetLedger() string {
+	if x != nil {
+		return x.Ledger
+	}
+	return ""
+}
+
+func (x *Evidence) GetBlocks() []uint64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type Verdict struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Check      string      `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	Message    string      `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Identity   string      `protobuf:"bytes,3,opt,name=identity,proto3" json:"identity,omitempty"`
+	Type       VerdictType `protobuf:"varint,4,opt,name=type,proto3,enum=judge.VerdictType" json:"type,omitempty"`
+	Category   string      `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	Evidence   []*Evidence `protobuf:"bytes,6,rep,name=evidence,proto3" json:"evidence,omitempty"`
+	Evaluation string      `protobuf:"bytes,7,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	// bundle is the name of the evidence bundle, which proves the verdict
+	Bundle string `protobuf:"bytes,8,opt,name=bundle,proto3" json:"bundle,omitempty"`
+}
+
+func (x *Verdict) Reset() {
+	*x = Verdict{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Verdict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Verdict) ProtoMessage() {}
+
+func (x *Verdict) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Verdict.ProtoReflect.Descriptor instead.
+func (*Verdict) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Verdict) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *Verdict) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Verdict) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *Verdict) GetType() VerdictType {
+	if x != nil {
+		return x.Type
+	}
+	return VerdictType_KAFKA_CLUSTER
+}
+
+func (x *Verdict) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Verdict) GetEvidence() []*Evidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+func (x *Verdict) GetEvaluation() string {
+	if x != nil {
+		return x.Evaluation
+	}
+	return ""
+}
+
+func (x *Verdict) GetBundle() string {
+	if x != nil {
+		return x.Bundle
+	}
+	return ""
+}
+
+type Phase struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Check       string `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Passed      bool   `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+}
+
+func (x *Phase) Reset() {
+	*x = Phase{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Phase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Phase) ProtoMessage() {}
+
+func (x *Phase) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Phase.ProtoReflect.Descriptor instead.
+func (*Phase) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Phase) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *Phase) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Phase) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+type Ledger struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identity string `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Height   uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *Ledger) Reset() {
+	*x = Ledger{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ledger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ledger) ProtoMessage() {}
+
+func (x *Ledger) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ledger.ProtoReflect.Descriptor instead.
+func (*Ledger) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Ledger) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *Ledger) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type CaseReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CaseId        string     `protobuf:"bytes,1,opt,name=case_id,json=caseId,proto3" json:"case_id,omitempty"`
+	JudgeVersion  string     `protobuf:"bytes,2,opt,name=judge_version,json=judgeVersion,proto3" json:"judge_version,omitempty"`
+	ConsensusType string     `protobuf:"bytes,3,opt,name=consensus_type,json=consensusType,proto3" json:"consensus_type,omitempty"`
+	ChannelName   string     `protobuf:"bytes,4,opt,name=channel_name,json=channelName,proto3" json:"channel_name,omitempty"`
+	Ledgers       []*Ledger  `protobuf:"bytes,5,rep,name=ledgers,proto3" json:"ledgers,omitempty"`
+	Phases        []*Phase   `protobuf:"bytes,6,rep,name=phases,proto3" json:"phases,omitempty"`
+	Consistent    bool       `protobuf:"varint,7,opt,name=consistent,proto3" json:"consistent,omitempty"`
+	Check         string     `protobuf:"bytes,8,opt,name=check,proto3" json:"check,omitempty"`
+	Verdicts      []*Verdict `protobuf:"bytes,9,rep,name=verdicts,proto3" json:"verdicts,omitempty"`
+	// certificate is the signed verdict certificate, if the service signs its reports
+	Certificate []byte `protobuf:"bytes,10,opt,name=certificate,proto3" json:"certificate,omitempty"`
+}
+
+func (x *CaseReport) Reset() {
+	*x = CaseReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CaseReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseReport) ProtoMessage() {}
+
+func (x *CaseReport) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseReport.ProtoReflect.Descriptor instead.
+func (*CaseReport) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CaseReport) GetCaseId() string {
+	if x != nil {
+		return x.CaseId
+	}
+	return ""
+}
+
+func (x *CaseReport) GetJudgeVersion() string {
+	if x != nil {
+		return x.JudgeVersion
+	}
+	return ""
+}
+
+func (x *CaseReport) GetConsensusType() string {
+	if x != nil {
+		return x.ConsensusType
+	}
+	return ""
+}
+
+func (x *CaseReport) GetChannelName() string {
+	if x != nil {
+		return x.ChannelName
+	}
+	return ""
+}
+
+func (x *CaseReport) GetLedgers() []*Ledger {
+	if x != nil {
+		return x.Ledgers
+	}
+	return nil
+}
+
+func (x *CaseReport) GetPhases() []*Phase {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+func (x *CaseReport) GetConsistent() bool {
+	if x != nil {
+		return x.Consistent
+	}
+	return false
+}
+
+func (x *CaseReport) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *CaseReport) GetVerdicts() []*Verdict {
+	if x != nil {
+		return x.Verdicts
+	}
+	return nil
+}
+
+func (x *CaseReport) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+type CaseEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*CaseEvent_Progress
+	//	*CaseEvent_Status
+	//	*CaseEvent_Report
+	Event isCaseEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *CaseEvent) Reset() {
+	*x = CaseEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_judge_judge_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CaseEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaseEvent) ProtoMessage() {}
+
+func (x *CaseEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_judge_judge_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaseEvent.ProtoReflect.Descriptor instead.
+func (*CaseEvent) Descriptor() ([]byte, []int) {
+	return file_judge_judge_proto_rawDescGZIP(), []int{10}
+}
+
+func (m *CaseEvent) GetEvent() isCaseEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *CaseEvent) GetProgress() string {
+	if x, ok := x.GetEvent().(*CaseEvent_Progress); ok {
+		return x.Progress
+	}
+	return ""
+}
+
+func (x *CaseEvent) GetStatus() *CaseStatus {
+	if x, ok := x.GetEvent().(*CaseEvent_Status); ok {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *CaseEvent) GetReport() *CaseReport {
+	if x, ok := x.GetEvent().(*CaseEvent_Report); ok {
+		return x.Report
+	}
+	return nil
+}
+
+type isCaseEvent_Event interface {
+	isCaseEvent_Event()
+}
+
+type CaseEvent_Progress struct {
+	Progress string `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type CaseEvent_Status struct {
+	Status *CaseStatus `protobuf:"bytes,2,opt,name=status,proto3,oneof"`
+}
+
+type CaseEvent_Report struct {
+	Report *CaseReport `protobuf:"bytes,3,opt,name=report,proto3,oneof"`
+}
+
+func (*CaseEvent_Progress) isCaseEvent_Event() {}
+
+func (*CaseEvent_Status) isCaseEvent_Event() {}
+
+func (*CaseEvent_Report) isCaseEvent_Event() {}
+
+var File_judge_judge_proto protoreflect.FileDescriptor
+
+var file_judge_judge_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2f, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x1a, 0x13, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xa0, 0x03, 0x0a, 0x0b, 0x43, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x12,
+	0x20, 0x0a, 0x0c, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x4b, 0x65, 0x79, 0x49,
+	0x64, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x72, 0x65, 0x64, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d,
+	0x61, 0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x62, 0x73, 0x6f, 0x6c,
+	0x75, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x10, 0x61, 0x62, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x65, 0x4d, 0x61, 0x78,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x72,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23,
+	0x0a, 0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x6f,
+	0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74,
+	0x69, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x49,
+	0x64, 0x73, 0x22, 0x21, 0x0a, 0x06, 0x43, 0x61, 0x73, 0x65, 0x49, 0x44, 0x12, 0x17, 0x0a, 0x07,
+	0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x88, 0x01, 0x0a, 0x0a, 0x43, 0x61, 0x73, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x26, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6a,
+	0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0c, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x65, 0x65, 0x72, 0x73,
+	0x22, 0x5f, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x05,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x22, 0x6c, 0x0a, 0x0d, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x65, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22,
+	0x3a, 0x0a, 0x08, 0x45, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x65, 0x64, 0x67, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x64,
+	0x67, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0xfe, 0x01, 0x0a, 0x07,
+	0x56, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x2b, 0x0a, 0x08, 0x65, 0x76, 0x69, 0x64, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6a, 0x75, 0x64, 0x67,
+	0x65, 0x2e, 0x45, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x65, 0x76, 0x69, 0x64,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x76, 0x61, 0x6c, 0x75, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x22, 0x57, 0x0a, 0x05,
+	0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70,
+	0x61, 0x73, 0x73, 0x65, 0x64, 0x22, 0x3c, 0x0a, 0x06, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x22, 0xe7, 0x02, 0x0a, 0x0a, 0x43, 0x61, 0x73, 0x65, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6a,
+	0x75, 0x64, 0x67, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x07, 0x6c, 0x65,
+	0x64, 0x67, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6a, 0x75,
+	0x64, 0x67, 0x65, 0x2e, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x52, 0x07, 0x6c, 0x65, 0x64, 0x67,
+	0x65, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x50, 0x68, 0x61, 0x73,
+	0x65, 0x52, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e,
+	0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63,
+	0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12,
+	0x2a, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63,
+	0x74, 0x52, 0x08, 0x76, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0x8c, 0x01,
+	0x0a, 0x09, 0x43, 0x61, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6a, 0x75, 0x64, 0x67,
+	0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x43,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2a, 0x63, 0x0a, 0x09,
+	0x43, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x57, 0x41,
+	0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x10, 0x00, 0x12, 0x0a,
+	0x0a, 0x06, 0x51, 0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55,
+	0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45,
+	0x45, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x04, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10,
+	0x05, 0x2a, 0x4d, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x11, 0x0a, 0x0d, 0x4b, 0x41, 0x46, 0x4b, 0x41, 0x5f, 0x43, 0x4c, 0x55, 0x53, 0x54, 0x45,
+	0x52, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x45, 0x52, 0x10, 0x01,
+	0x12, 0x08, 0x0a, 0x04, 0x50, 0x45, 0x45, 0x52, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x52,
+	0x44, 0x45, 0x52, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x10, 0x03,
+	0x32, 0xe1, 0x01, 0x0a, 0x05, 0x4a, 0x75, 0x64, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x43, 0x61, 0x73, 0x65, 0x12, 0x12, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65,
+	0x2e, 0x43, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x6a,
+	0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
+	0x00, 0x12, 0x3c, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x12, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x1a, 0x14, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x4c, 0x65,
+	0x64, 0x67, 0x65, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x22, 0x00, 0x28, 0x01, 0x12,
+	0x31, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x64, 0x69, 0x63, 0x74, 0x73, 0x12, 0x0d,
+	0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x49, 0x44, 0x1a, 0x11, 0x2e,
+	0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x22, 0x00, 0x12, 0x30, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x61, 0x73, 0x65, 0x12,
+	0x0d, 0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x49, 0x44, 0x1a, 0x10,
+	0x2e, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x22, 0x00, 0x30, 0x01, 0x42, 0x5d, 0x0a, 0x29, 0x6f, 0x72, 0x67, 0x2e, 0x68, 0x79, 0x70, 0x65,
+	0x72, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x2e, 0x66, 0x61, 0x62, 0x72, 0x69, 0x63, 0x5f, 0x6a,
+	0x75, 0x64, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x6a, 0x75, 0x64, 0x67,
+	0x65, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x79,
+	0x70, 0x65, 0x72, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x2f, 0x66, 0x61, 0x62, 0x72, 0x69, 0x63,
+	0x5f, 0x6a, 0x75, 0x64, 0x67, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6a, 0x75,
+	0x64, 0x67, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_judge_judge_proto_rawDescOnce sync.Once
+	file_judge_judge_proto_rawDescData = file_judge_judge_proto_rawDesc
+)
+
+func file_judge_judge_proto_rawDescGZIP() []byte {
+	file_judge_judge_proto_rawDescOnce.Do(func() {
+		file_judge_judge_proto_rawDescData = protoimpl.X.CompressGZIP(file_judge_judge_proto_rawDescData)
+	})
+	return file_judge_judge_proto_rawDescData
+}
+
+var file_judge_judge_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_judge_judge_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_judge_judge_proto_goTypes = []interface{}{
+	(CaseState)(0),        // 0: judge.CaseState
+	(VerdictType)(0),      // 1: judge.VerdictType
+	(*CaseRequest)(nil),   // 2: judge.CaseRequest
+	(*CaseID)(nil),        // 3: judge.CaseID
+	(*CaseStatus)(nil),    // 4: judge.CaseStatus
+	(*BlockUpload)(nil),   // 5: judge.BlockUpload
+	(*LedgerReceipt)(nil), // 6: judge.LedgerReceipt
+	(*Evidence)(nil),      // 7: judge.Evidence
+	(*Verdict)(nil),       // 8: judge.Verdict
+	(*Phase)(nil),         // 9: judge.Phase
+	(*Ledger)(nil),        // 10: judge.Ledger
+	(*CaseReport)(nil),    // 11: judge.CaseReport
+	(*CaseEvent)(nil),     // 12: judge.CaseEvent
+	(*common.Block)(nil),  // 13: common.Block
+}
+var file_judge_judge_proto_depIdxs = []int32{
+	0,  // 0: judge.CaseStatus.state:type_name -> judge.CaseState
+	13, // 1: judge.BlockUpload.block:type_name -> common.Block
+	1,  // 2: judge.Verdict.type:type_name -> judge.VerdictType
+	7,  // 3: judge.Verdict.evidence:type_name -> judge.Evidence
+	10, // 4: judge.CaseReport.ledgers:type_name -> judge.Ledger
+	9,  // 5: judge.CaseReport.phases:type_name -> judge.Phase
+	8,  // 6: judge.CaseReport.verdicts:type_name -> judge.Verdict
+	4,  // 7: judge.CaseEvent.status:type_name -> judge.CaseStatus
+	11, // 8: judge.CaseEvent.report:type_name -> judge.CaseReport
+	2,  // 9: judge.Judge.SubmitCase:input_type -> judge.CaseRequest
+	5,  // 10: judge.Judge.StreamBlocks:input_type -> judge.BlockUpload
+	3,  // 11: judge.Judge.GetVerdicts:input_type -> judge.CaseID
+	3,  // 12: judge.Judge.WatchCase:input_type -> judge.CaseID
+	4,  // 13: judge.Judge.SubmitCase:output_type -> judge.CaseStatus
+	6,  // 14: judge.Judge.StreamBlocks:output_type -> judge.LedgerReceipt
+	11, // 15: judge.Judge.GetVerdicts:output_type -> judge.CaseReport
+	12, // 16: judge.Judge.WatchCase:output_type -> judge.CaseEvent
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_judge_judge_proto_init() }
+func file_judge_judge_proto_init() {
+	if File_judge_judge_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_judge_judge_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockUpload); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LedgerReceipt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Evidence); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Verdict); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Phase); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ledger); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_judge_judge_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CaseEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_judge_judge_proto_msgTypes[10].OneofWrappers = []interface{}{
+		(*CaseEvent_Progress)(nil),
+		(*CaseEvent_Status)(nil),
+		(*CaseEvent_Report)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_judge_judge_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_judge_judge_proto_goTypes,
+		DependencyIndexes: file_judge_judge_proto_depIdxs,
+		EnumInfos:         file_judge_judge_proto_enumTypes,
+		MessageInfos:      file_judge_judge_proto_msgTypes,
+	}.Build()
+	File_judge_judge_proto = out.File
+	file_judge_judge_proto_rawDesc = nil
+	file_judge_judge_proto_goTypes = nil
+	file_judge_judge_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// JudgeClient is the client API for Judge service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type JudgeClient interface {
+	// SubmitCase opens a case for the channel. The case is judged as soon as the ledgers of all its peers were uploaded
+	SubmitCase(ctx context.Context, in *CaseRequest, opts ...grpc.CallOption) (*CaseStatus, error)
+	// StreamBlocks uploads the ledger of a single peer block by block, starting with the genesis block.
+	// The first message names the case and the peer, the ledger is complete when the client closes the stream
+	StreamBlocks(ctx context.Context, opts ...grpc.CallOption) (Judge_StreamBlocksClient, error)
+	// GetVerdicts returns the report of a finished case
+	GetVerdicts(ctx context.Context, in *CaseID, opts ...grpc.CallOption) (*CaseReport, error)
+	// WatchCase streams the progress of a case and its status changes. The last event contains the report of the finished case
+	WatchCase(ctx context.Context, in *CaseID, opts ...grpc.CallOption) (Judge_WatchCaseClient, error)
+}
+
+type judgeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJudgeClient(cc grpc.ClientConnInterface) JudgeClient {
+	return &judgeClient{cc}
+}
+
+func (c *judgeClient) SubmitCase(ctx context.Context, in *CaseRequest, opts ...grpc.CallOption) (*CaseStatus, error) {
+	out := new(CaseStatus)
+	err := c.cc.Invoke(ctx, "/judge.Judge/SubmitCase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *judgeClient) StreamBlocks(ctx context.Context, opts ...grpc.CallOption) (Judge_StreamBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Judge_serviceDesc.Streams[0], "/judge.Judge/StreamBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &judgeStreamBlocksClient{stream}
+	return x, nil
+}
+
+type Judge_StreamBlocksClient interface {
+	Send(*BlockUpload) error
+	CloseAndRecv() (*LedgerReceipt, error)
+	grpc.ClientStream
+}
+
+type judgeStreamBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *judgeStreamBlocksClient) Send(m *BlockUpload) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *judgeStreamBlocksClient) CloseAndRecv() (*LedgerReceipt, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(LedgerReceipt)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *judgeClient) GetVerdicts(ctx context.Context, in *CaseID, opts ...grpc.CallOption) (*CaseReport, error) {
+	out := new(CaseReport)
+	err := c.cc.Invoke(ctx, "/judge.Judge/GetVerdicts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *judgeClient) WatchCase(ctx context.Context, in *CaseID, opts ...grpc.CallOption) (Judge_WatchCaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Judge_serviceDesc.Streams[1], "/judge.Judge/WatchCase", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &judgeWatchCaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Judge_WatchCaseClient interface {
+	Recv() (*CaseEvent, error)
+	grpc.ClientStream
+}
+
+type judgeWatchCaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *judgeWatchCaseClient) Recv() (*CaseEvent, error) {
+	m := new(CaseEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// JudgeServer is the server API for Judge service.
+type JudgeServer interface {
+	// SubmitCase opens a case for the channel. The case is judged as soon as the ledgers of all its peers were uploaded
+	SubmitCase(context.Context, *CaseRequest) (*CaseStatus, error)
+	// StreamBlocks uploads the ledger of a single peer block by block, starting with the genesis block.
+	// The first message names the case and the peer, the ledger is complete when the client closes the stream
+	StreamBlocks(Judge_StreamBlocksServer) error
+	// GetVerdicts returns the report of a finished case
+	GetVerdicts(context.Context, *CaseID) (*CaseReport, error)
+	// WatchCase streams the progress of a case and its status changes. The last event contains the report of the finished case
+	WatchCase(*CaseID, Judge_WatchCaseServer) error
+}
+
+// UnimplementedJudgeServer can be embedded to have forward compatible implementations.
+type UnimplementedJudgeServer struct {
+}
+
+func (*UnimplementedJudgeServer) SubmitCase(context.Context, *CaseRequest) (*CaseStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitCase not implemented")
+}
+func (*UnimplementedJudgeServer) StreamBlocks(Judge_StreamBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
+func (*UnimplementedJudgeServer) GetVerdicts(context.Context, *CaseID) (*CaseReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVerdicts not implemented")
+}
+func (*UnimplementedJudgeServer) WatchCase(*CaseID, Judge_WatchCaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCase not implemented")
+}
+
+func RegisterJudgeServer(s *grpc.Server, srv JudgeServer) {
+	s.RegisterService(&_Judge_serviceDesc, srv)
+}
+
+func _Judge_SubmitCase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgeServer).SubmitCase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/judge.Judge/SubmitCase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgeServer).SubmitCase(ctx, req.(*CaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Judge_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JudgeServer).StreamBlocks(&judgeStreamBlocksServer{stream})
+}
+
+type Judge_StreamBlocksServer interface {
+	SendAndClose(*LedgerReceipt) error
+	Recv() (*BlockUpload, error)
+	grpc.ServerStream
+}
+
+type judgeStreamBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *judgeStreamBlocksServer) SendAndClose(m *LedgerReceipt) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *judgeStreamBlocksServer) Recv() (*BlockUpload, error) {
+	m := new(BlockUpload)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Judge_GetVerdicts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaseID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JudgeServer).GetVerdicts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/judge.Judge/GetVerdicts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JudgeServer).GetVerdicts(ctx, req.(*CaseID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Judge_WatchCase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CaseID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JudgeServer).WatchCase(m, &judgeWatchCaseServer{stream})
+}
+
+type Judge_WatchCaseServer interface {
+	Send(*CaseEvent) error
+	grpc.ServerStream
+}
+
+type judgeWatchCaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *judgeWatchCaseServer) Send(m *CaseEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Judge_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "judge.Judge",
+	HandlerType: (*JudgeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitCase",
+			Handler:    _Judge_SubmitCase_Handler,
+		},
+		{
+			MethodName: "GetVerdicts",
+			Handler:    _Judge_GetVerdicts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _Judge_StreamBlocks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchCase",
+			Handler:       _Judge_WatchCase_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "judge/judge.proto",
+}
@@ -0,0 +1,55 @@
+package raft
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyBatchSizes checks every block against the BatchSize of the channel config in effect.
+// Since Raft does not persist the stream of received messages, only violations of the limits can be detected:
+// Blocks must not exceed MaxMessageCount, no envelope may exceed AbsoluteMaxBytes, batches of several envelopes must not exceed PreferredMaxBytes
+// and config transactions must be isolated in their own block
+func (v *Verifier) VerifyBatchSizes() []*verdicts.Verdict {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	// starting at i = 1, since the envelopes of the genesis block were not ordered
+	for i := 1; i < len(v.Envelopes); i++ {
+		blockNumber := v.BlockNumbers[i]
+
+		batchSize, err := channelconfig.GetBatchSize(configs[i])
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to read the BatchSize in effect for block %d: %s", blockNumber, err), v.Identity, 1)}
+		}
+
+		if channelconfig.IsConfigBlock(v.Blocks[i]) {
+			if len(v.Envelopes[i]) != 1 {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer did not isolate the config transaction of block %d, which contains %d envelopes", blockNumber, len(v.Envelopes[i])), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			continue
+		}
+
+		if len(v.Envelopes[i]) > int(batchSize.MaxMessageCount) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d contains %d envelopes, but MaxMessageCount is %d", blockNumber, len(v.Envelopes[i]), batchSize.MaxMessageCount), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+
+		batchSizeBytes := 0
+		for tIdx, env := range v.Envelopes[i] {
+			if wireSize := validator.EnvelopeWireSize(env); wireSize > int(batchSize.AbsoluteMaxBytes) {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer accepted envelope %d of block %d with %d bytes, which exceeds AbsoluteMaxBytes %d", tIdx, blockNumber, wireSize, batchSize.AbsoluteMaxBytes), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			batchSizeBytes += validator.FabricMessageSizeBytes(env)
+		}
+
+		if len(v.Envelopes[i]) > 1 && batchSizeBytes > int(batchSize.PreferredMaxBytes) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d contains %d envelopes with %d bytes, which exceeds PreferredMaxBytes %d", blockNumber, len(v.Envelopes[i]), batchSizeBytes, batchSize.PreferredMaxBytes), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+	}
+
+	return nil
+}
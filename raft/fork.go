@@ -0,0 +1,106 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// forkVersion is one of several different blocks, which the peers committed at the same height
+type forkVersion struct {
+	headerHash []byte
+	verifiers  []*Verifier
+}
+
+// FindFirstFork compares the ledgers of all peers block by block and returns verdicts for the first height, at which they diverge.
+// Raft never commits two different blocks at the same height, thus the orderers which signed the conflicting blocks are blamed.
+// If an orderer signed more than one version, only this orderer is blamed. A version without any valid orderer signature is blamed on the peers which committed it
+func FindFirstFork(verifiers ...*Verifier) []*verdicts.Verdict {
+	height := -1
+	for _, v := range verifiers {
+		if height == -1 || len(v.Blocks) < height {
+			height = len(v.Blocks)
+		}
+	}
+
+	for i := 0; i < height; i++ {
+		versions := make([]*forkVersion, 0)
+		for _, v := range verifiers {
+			headerHash := validator.BlockHeaderHash(v.Blocks[i].Header)
+			found := false
+			for _, version := range versions {
+				if bytes.Equal(version.headerHash, headerHash) {
+					version.verifiers = append(version.verifiers, v)
+					found = true
+					break
+				}
+			}
+			if !found {
+				versions = append(versions, &forkVersion{headerHash: headerHash, verifiers: []*Verifier{v}})
+			}
+		}
+
+		if len(versions) > 1 {
+			return blameFork(i, versions)
+		}
+	}
+
+	return nil
+}
+
+func blameFork(i int, versions []*forkVersion) []*verdicts.Verdict {
+	var result []*verdicts.Verdict
+
+	blockNumber := versions[0].verifiers[0].BlockNumbers[i]
+
+	// signedVersions maps the name of every orderer to the header hashes of the versions it signed
+	signedVersions := make(map[string][]string)
+
+	for _, version := range versions {
+		versionHash := hex.EncodeToString(version.headerHash)
+		signers, err := version.verifiers[0].ordererSignersOfBlock(i)
+		if err != nil || len(signers) == 0 {
+			for _, v := range version.verifiers {
+				result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Peer committed block %d with header hash %s, which is not signed by any orderer and differs from the block of other peers", v.BlockNumbers[i], versionHash), v.Identity, 2))
+			}
+			continue
+		}
+		for _, signer := range signers {
+			signedVersions[signer.String()] = append(signedVersions[signer.String()], versionHash)
+		}
+	}
+
+	signerNames := make([]string, 0, len(signedVersions))
+	for name := range signedVersions {
+		signerNames = append(signerNames, name)
+	}
+	sort.Strings(signerNames)
+
+	equivocated := false
+	for _, name := range signerNames {
+		if len(signedVersions[name]) > 1 {
+			equivocated = true
+			result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Orderer signed %d different blocks at height %d: %v", len(signedVersions[name]), blockNumber, signedVersions[name]), name, 1))
+		}
+	}
+	if equivocated {
+		return withForkEvidence(result, i, versions)
+	}
+
+	for _, name := range signerNames {
+		result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Ledgers fork at block %d, orderer signed block %s, which conflicts with another signed block at the same height", blockNumber, signedVersions[name][0]), name, 1))
+	}
+	return withForkEvidence(result, i, versions)
+}
+
+// withForkEvidence references block i of every version as evidence of the verdicts
+func withForkEvidence(result []*verdicts.Verdict, i int, versions []*forkVersion) []*verdicts.Verdict {
+	for _, version := range versions {
+		verdicts.AttachEvidence(result, version.verifiers[0].Identity, version.verifiers[0].BlockNumbers[i])
+	}
+	return result
+}
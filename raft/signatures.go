@@ -0,0 +1,67 @@
+package raft
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	"github.com/hyperledger/fabric_judge/msp"
+	validator "github.com/hyperledger/fabric_judge/validator"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyBlockSignatures checks that the blocks form a hash chain and that every block is signed by at least one orderer of the channel.
+// The peer must not have committed a block violating either property, thus the verdicts are rendered against the peer
+func (v *Verifier) VerifyBlockSignatures() []*verdicts.Verdict {
+	invalid, err := validator.VerifyHashChain(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 2).WithEvidence(v.Identity, validator.HashChainEvidence(v.Blocks, invalid)...)}
+	}
+
+	return v.VerifyOrdererSignatures()
+}
+
+// VerifyOrdererSignatures checks that every block is signed by at least one orderer of the config in effect, without verifying the hash chain
+func (v *Verifier) VerifyOrdererSignatures() []*verdicts.Verdict {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	var mspManager *msp.Manager
+
+	// starting at i = 1, since the genesis block is not signed by the orderers
+	for i := 1; i < len(v.Blocks); i++ {
+		block := v.Blocks[i]
+
+		// the MSPs only need to be rebuilt, if the block was ordered under a different config
+		if mspManager == nil || configs[i] != configs[i-1] {
+			mspManager, err = msp.NewManagerFromConfig(configs[i])
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to build the MSPs in effect for block %d: %s", block.Header.Number, err), v.Identity, 1)}
+			}
+		}
+
+		signers, err := validator.GetOrdererSignersOfBlock(block, mspManager)
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 2)}
+		}
+		if len(signers) == 0 {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Peer committed block %d without a valid orderer signature", block.Header.Number), v.Identity, 2).WithEvidence(v.Identity, block.Header.Number)}
+		}
+	}
+
+	return nil
+}
+
+// ordererSignersOfBlock returns the orderer identities, which validly signed block i under the config in effect
+func (v *Verifier) ordererSignersOfBlock(i int) ([]*msp.Identity, error) {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks)
+	if err != nil {
+		return nil, err
+	}
+	mspManager, err := msp.NewManagerFromConfig(configs[i])
+	if err != nil {
+		return nil, err
+	}
+	return validator.GetOrdererSignersOfBlock(v.Blocks[i], mspManager)
+}
@@ -0,0 +1,148 @@
+package raft
+
+import (
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	"github.com/hyperledger/fabric_judge/protos/orderer/etcdraft"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// Verifier contains the envelopes and the etcdraft metadata of the blocks received by a single peer
+type Verifier struct {
+	Blocks       []*cb.Block
+	Envelopes    [][]*cb.Envelope
+	RaftMetadata []*etcdraft.BlockMetadata
+	BlockNumbers []uint64
+	Identity     string
+}
+
+// NewVerifier extracts the envelopes and the etcdraft metadata from the given blocks
+func NewVerifier(blocks []*cb.Block, identity string) (*Verifier, error) {
+	verifier := &Verifier{
+		Blocks:       blocks,
+		Envelopes:    make([][]*cb.Envelope, 0),
+		RaftMetadata: make([]*etcdraft.BlockMetadata, 0),
+		BlockNumbers: make([]uint64, 0),
+		Identity:     identity,
+	}
+
+	for _, block := range blocks {
+		blockEnv := make([]*cb.Envelope, 0)
+		for tIdx, data := range block.Data.Data {
+			env := new(cb.Envelope)
+			err := proto.Unmarshal(data, env)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to unmarshal envelope %d of block %d of %s: %s", tIdx, block.Header.Number, identity, err)
+			}
+			blockEnv = append(blockEnv, env)
+		}
+		verifier.Envelopes = append(verifier.Envelopes, blockEnv)
+
+		raftMetadata, err := GetRaftMetadataFromBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		verifier.RaftMetadata = append(verifier.RaftMetadata, raftMetadata)
+		verifier.BlockNumbers = append(verifier.BlockNumbers, block.Header.Number)
+	}
+
+	return verifier, nil
+}
+
+// GetRaftMetadataFromBlock decodes the EtcdRaftBlockMetadata of the block.
+// Fabric 1.4 stores it in the ORDERER metadata, whereas later versions store it as consenter metadata along with the SIGNATURES
+func GetRaftMetadataFromBlock(block *cb.Block) (*etcdraft.BlockMetadata, error) {
+	raftMetadata := &etcdraft.BlockMetadata{}
+	if block.Metadata == nil {
+		return raftMetadata, nil
+	}
+
+	var consenterMetadata []byte
+	if len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_ORDERER) {
+		ordererMetadata := &cb.Metadata{}
+		err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER], ordererMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to unmarshal ORDERER metadata of block %d: %s", block.Header.Number, err)
+		}
+		consenterMetadata = ordererMetadata.Value
+	}
+
+	if len(consenterMetadata) == 0 && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_SIGNATURES) {
+		signaturesMetadata := &cb.Metadata{}
+		err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES], signaturesMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to unmarshal SIGNATURES metadata of block %d: %s", block.Header.Number, err)
+		}
+		ordererBlockMetadata := &cb.OrdererBlockMetadata{}
+		err = proto.Unmarshal(signaturesMetadata.Value, ordererBlockMetadata)
+		if err == nil {
+			consenterMetadata = ordererBlockMetadata.ConsenterMetadata
+		}
+	}
+
+	err := proto.Unmarshal(consenterMetadata, raftMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal etcdraft metadata of block %d: %s", block.Header.Number, err)
+	}
+	return raftMetadata, nil
+}
+
+// VerifyRaftMetadata checks the etcdraft metadata of every block against the consenters of the channel config:
+// Every consenter must have a unique id below NextConsenterId, NextConsenterId must never go backwards and the Raft index must strictly increase
+func (v *Verifier) VerifyRaftMetadata() []*verdicts.Verdict {
+	configs, err := channelconfig.GetConfigsInEffect(v.Blocks)
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	var lastRaftIndex, lastNextConsenterID uint64
+
+	// starting at i = 1, since the genesis block was not ordered by Raft
+	for i := 1; i < len(v.RaftMetadata); i++ {
+		raftMetadata := v.RaftMetadata[i]
+		blockNumber := v.BlockNumbers[i]
+
+		// a config block already lists the consenters of the config it contains
+		config := configs[i]
+		if channelconfig.IsConfigBlock(v.Blocks[i]) {
+			config, err = channelconfig.GetConfigFromBlock(v.Blocks[i])
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to read the config of config block %d: %s", blockNumber, err), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+		}
+
+		configMetadata, err := channelconfig.GetRaftConfigMetadata(config)
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to read the consenters in effect for block %d: %s", blockNumber, err), v.Identity, 1)}
+		}
+
+		if len(raftMetadata.ConsenterIds) != len(configMetadata.Consenters) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d lists %d consenter ids, but the channel config contains %d consenters", blockNumber, len(raftMetadata.ConsenterIds), len(configMetadata.Consenters)), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		consenterIDs := make(map[uint64]bool)
+		for _, id := range raftMetadata.ConsenterIds {
+			if consenterIDs[id] {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d lists consenter id %d twice", blockNumber, id), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			if id >= raftMetadata.NextConsenterId {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Consenter id %d of block %d is not below NextConsenterId %d", id, blockNumber, raftMetadata.NextConsenterId), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			consenterIDs[id] = true
+		}
+		if raftMetadata.NextConsenterId < lastNextConsenterID {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("NextConsenterId of block %d (%d) goes backwards (previously %d)", blockNumber, raftMetadata.NextConsenterId, lastNextConsenterID), v.Identity, 1).WithEvidence(v.Identity, blockNumber-1, blockNumber)}
+		}
+
+		if i > 1 && raftMetadata.RaftIndex <= lastRaftIndex {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Raft index of block %d (%d) does not advance beyond the one of the previous block (%d)", blockNumber, raftMetadata.RaftIndex, lastRaftIndex), v.Identity, 1).WithEvidence(v.Identity, blockNumber-1, blockNumber)}
+		}
+
+		lastRaftIndex = raftMetadata.RaftIndex
+		lastNextConsenterID = raftMetadata.NextConsenterId
+	}
+
+	return nil
+}
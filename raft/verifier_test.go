@@ -0,0 +1,72 @@
+package raft_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	"github.com/hyperledger/fabric_judge/raft"
+)
+
+// verifyRaftMetadata returns the message of the first verdict of VerifyRaftMetadata
+func verifyRaftMetadata(t *testing.T, blocks []*cb.Block) string {
+	v, err := raft.NewVerifier(blocks, "peerA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verdict := v.VerifyRaftMetadata()
+	if len(verdict) == 0 {
+		return ""
+	}
+	return verdict[0].Message()
+}
+
+func TestVerifyRaftMetadataAddConsenter(t *testing.T) {
+	f := ledgertest.NewRaftFixture(t, 3)
+	blocks := f.Chain([]string{"tx0", "tx1"})
+
+	// the config block lists the ids of the new consenters, before its config is in effect
+	consenters := append(f.Consenters, f.NewOrderer())
+	blocks = append(blocks, f.ConfigBlock(blocks[1], consenters))
+	blocks = append(blocks, f.Block(blocks[2], "tx2"))
+
+	if message := verifyRaftMetadata(t, blocks); message != "" {
+		t.Fatalf("Expected the added consenter to pass, got: %s", message)
+	}
+	v, err := raft.NewVerifier(blocks, "peerA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict := v.VerifyOrdererSignatures(); len(verdict) != 0 {
+		t.Fatalf("Expected the blocks to be signed by a consenter, got: %s", verdict[0].Message())
+	}
+}
+
+func TestVerifyRaftMetadataMissingConsenter(t *testing.T) {
+	f := ledgertest.NewRaftFixture(t, 3)
+	blocks := f.Chain([]string{"tx0"})
+	previous := f.Consenters
+	blocks = append(blocks, f.ConfigBlock(blocks[1], append(previous, f.NewOrderer())))
+
+	// the block after the config block omits the added consenter
+	f.Consenters = previous
+	blocks = append(blocks, f.Block(blocks[2], "tx1"))
+
+	if message := verifyRaftMetadata(t, blocks); message != "Block 3 lists 3 consenter ids, but the channel config contains 4 consenters" {
+		t.Fatalf("Expected the missing consenter to be detected, got: %q", message)
+	}
+}
+
+func TestVerifyRaftMetadataRaftIndex(t *testing.T) {
+	f := ledgertest.NewRaftFixture(t, 3)
+	blocks := f.Chain([]string{"tx0"}, []string{"tx1"})
+	// a block of another fixture starts again at Raft index 1
+	stale := ledgertest.NewRaftFixture(t, 3).Block(blocks[1], "tx1")
+	if message := verifyRaftMetadata(t, blocks); message != "" {
+		t.Fatalf("Expected the Raft index to advance, got: %s", message)
+	}
+
+	if message := verifyRaftMetadata(t, []*cb.Block{blocks[0], blocks[1], stale}); message != "Raft index of block 2 (1) does not advance beyond the one of the previous block (1)" {
+		t.Fatalf("Expected the Raft index going backwards to be detected, got: %q", message)
+	}
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/judge"
+	pb "github.com/hyperledger/fabric_judge/protos/judge"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxGrpcMessageBytes limits the size of a single gRPC message, i.e. of a single uploaded block
+const MaxGrpcMessageBytes = 100 << 20
+
+// JudgeService implements the gRPC API of the judge. A case is a job of the Server, whose ledgers are streamed block by
+// block. The job is queued as soon as the ledgers of all peers of the case were uploaded
+type JudgeService struct {
+	server *Server
+
+	mu    sync.Mutex
+	cases map[string]*judgeCase
+}
+
+// judgeCase is a case, whose ledgers are uploaded. The lock of the service guards its fields
+type judgeCase struct {
+	id      string
+	dir     string
+	channel string
+	run     *judge.Job
+	peers   []string
+	// uploading and uploaded mark the peers, whose ledgers are streamed or complete
+	uploading map[string]bool
+	uploaded  map[string]bool
+	job       *job
+	err       error
+	// updated is closed and replaced on every change of the case, which wakes up all watchers
+	updated chan struct{}
+}
+
+// NewJudgeService creates the gRPC API of the judge, which runs its cases on the given server
+func NewJudgeService(server *Server) *JudgeService {
+	return &JudgeService{
+		server: server,
+		cases:  make(map[string]*judgeCase),
+	}
+}
+
+// SubmitCase opens a case, which awaits the ledgers of its peers
+func (js *JudgeService) SubmitCase(ctx context.Context, request *pb.CaseRequest) (*pb.CaseStatus, error) {
+	if len(request.Peers) < 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "Case needs the ledgers of at least two peers, but %d were given", len(request.Peers))
+	}
+	seen := make(map[string]bool)
+	for k, peer := range request.Peers {
+		if peer == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Peer %d of the case has no identity", k)
+		}
+		if seen[peer] {
+			return nil, status.Errorf(codes.InvalidArgument, "Peer %s is named twice", peer)
+		}
+		seen[peer] = true
+	}
+
+	id := js.server.newJobID()
+	dir := filepath.Join(js.server.config.WorkDir, id)
+	run, err := js.server.newJudgeJob(&JobRequest{
+		Channel:           request.Channel,
+		KafkaKeyID:        request.KafkaKeyId,
+		MaxBatchSize:      int(request.MaxBatchSize),
+		PreferredMaxBytes: int(request.PreferredMaxBytes),
+		AbsoluteMaxBytes:  int(request.AbsoluteMaxBytes),
+		OrdererVersion:    request.OrdererVersion,
+		BatchTimeout:      request.BatchTimeout,
+		TimingTolerance:   request.TimingTolerance,
+		Redact:            request.Redact,
+		RecipientKeyIDs:   request.RecipientKeyIds,
+	}, dir)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	c := &judgeCase{
+		id:        id,
+		dir:       dir,
+		channel:   request.Channel,
+		run:       run,
+		peers:     append([]string(nil), request.Peers...),
+		uploading: make(map[string]bool),
+		uploaded:  make(map[string]bool),
+		updated:   make(chan struct{}),
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.cases[id] = c
+	return c.status(), nil
+}
+
+// StreamBlocks stores the uploaded ledger of a peer. The blocks have to be sent in order, starting with the genesis block
+func (js *JudgeService) StreamBlocks(stream pb.Judge_StreamBlocksServer) error {
+	upload, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "Upload does not name a case and a peer")
+	}
+	if err != nil {
+		return err
+	}
+
+	c, dir, err := js.startUpload(upload.CaseId, upload.Peer)
+	if err != nil {
+		return err
+	}
+	height, digest, err := writeBlocks(stream, upload, dir, c.channel)
+	if err != nil {
+		os.RemoveAll(dir)
+		js.mu.Lock()
+		delete(c.uploading, upload.Peer)
+		js.mu.Unlock()
+		return err
+	}
+
+	err = js.finishUpload(c, upload.Peer)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.LedgerReceipt{
+		CaseId: c.id,
+		Peer:   upload.Peer,
+		Height: height,
+		Sha256: digest,
+	})
+}
+
+// startUpload reserves the upload of the ledger of the peer and returns its block directory
+func (js *JudgeService) startUpload(caseID string, peer string) (*judgeCase, string, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	c, ok := js.cases[caseID]
+	if !ok {
+		return nil, "", status.Errorf(codes.NotFound, "Case %s does not exist", caseID)
+	}
+	if c.job != nil || c.err != nil {
+		return nil, "", status.Errorf(codes.FailedPrecondition, "Case %s does not await blocks anymore", caseID)
+	}
+	for k, p := range c.peers {
+		if p != peer {
+			continue
+		}
+		if c.uploading[peer] || c.uploaded[peer] {
+			return nil, "", status.Errorf(codes.AlreadyExists, "Ledger of %s is already uploaded", peer)
+		}
+		c.uploading[peer] = true
+		return c, ledgerDir(c.dir, k), nil
+	}
+	return nil, "", status.Errorf(codes.InvalidArgument, "Peer %s is not part of case %s", peer, caseID)
+}
+
+// finishUpload marks the ledger of the peer as complete and queues the case, once all ledgers were uploaded
+func (js *JudgeService) finishUpload(c *judgeCase, peer string) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	delete(c.uploading, peer)
+	c.uploaded[peer] = true
+	defer c.notify()
+	if len(c.uploaded) < len(c.peers) {
+		return nil
+	}
+
+	for k, p := range c.peers {
+		c.run.Ledgers = append(c.run.Ledgers, &judge.LedgerSource{Identity: p, BlockDir: ledgerDir(c.dir, k)})
+	}
+	c.err = requireKafkaKey(c.run)
+	if c.err != nil {
+		return status.Error(codes.InvalidArgument, c.err.Error())
+	}
+	c.job, c.err = js.server.enqueue(c.id, c.dir, c.run)
+	if c.err != nil {
+		return status.Error(codes.Unavailable, c.err.Error())
+	}
+	return nil
+}
+
+// writeBlocks writes the streamed blocks into the directory and returns the height and digest of the ledger
+func writeBlocks(stream pb.Judge_StreamBlocksServer, upload *pb.BlockUpload, dir string, channel string) (uint64, string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return 0, "", status.Error(codes.Internal, err.Error())
+	}
+
+	hash := sha256.New()
+	height := uint64(0)
+	for {
+		if upload.Block == nil || upload.Block.Header == nil {
+			return 0, "", status.Errorf(codes.InvalidArgument, "Upload %d does not contain a block", height)
+		}
+		if upload.Block.Header.Number != height {
+			return 0, "", status.Errorf(codes.InvalidArgument, "Expected block %d, but received block %d", height, upload.Block.Header.Number)
+		}
+		blockBytes, err := proto.Marshal(upload.Block)
+		if err != nil {
+			return 0, "", status.Errorf(codes.InvalidArgument, "Unable to marshal block %d: %s", height, err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, channel+"_"+strconv.FormatUint(height, 10)+".block"), blockBytes, 0644)
+		if err != nil {
+			return 0, "", status.Error(codes.Internal, err.Error())
+		}
+		hash.Write(blockBytes)
+		height++
+
+		upload, err = stream.Recv()
+		if err == io.EOF {
+			return height, hex.EncodeToString(hash.Sum(nil)), nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+	}
+}
+
+// GetVerdicts returns the report of a finished case
+func (js *JudgeService) GetVerdicts(ctx context.Context, caseID *pb.CaseID) (*pb.CaseReport, error) {
+	c, err := js.lookup(caseID.CaseId)
+	if err != nil {
+		return nil, err
+	}
+	js.mu.Lock()
+	j := c.job
+	js.mu.Unlock()
+	if j == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Case %s awaits the ledgers of its peers", c.id)
+	}
+
+	report, finished := j.result()
+	if !finished {
+		return nil, status.Errorf(codes.FailedPrecondition, "Case %s has not finished yet", c.id)
+	}
+	if report == nil {
+		return nil, status.Errorf(codes.NotFound, "Case %s did not produce a report", c.id)
+	}
+	return newCaseReport(c.id, report)
+}
+
+// WatchCase streams the progress and status changes of a case until it has finished. The report is sent last
+func (js *JudgeService) WatchCase(caseID *pb.CaseID, stream pb.Judge_WatchCaseServer) error {
+	c, err := js.lookup(caseID.CaseId)
+	if err != nil {
+		return err
+	}
+
+	// the case awaits its ledgers until a job was queued for it
+	var j *job
+	for j == nil {
+		js.mu.Lock()
+		caseStatus, updated := c.status(), c.updated
+		j = c.job
+		js.mu.Unlock()
+		if j != nil {
+			break
+		}
+		err = stream.Send(&pb.CaseEvent{Event: &pb.CaseEvent_Status{Status: caseStatus}})
+		if err != nil {
+			return err
+		}
+		if caseStatus.State == pb.CaseState_FAILED {
+			return nil
+		}
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+
+	seen := 0
+	var lastState JobState
+	for {
+		jobStatus, progress, updated := j.changes(seen)
+		for _, message := range progress {
+			err = stream.Send(&pb.CaseEvent{Event: &pb.CaseEvent_Progress{Progress: message}})
+			if err != nil {
+				return err
+			}
+		}
+		seen += len(progress)
+
+		if jobStatus.State != lastState {
+			lastState = jobStatus.State
+			err = stream.Send(&pb.CaseEvent{Event: &pb.CaseEvent_Status{Status: &pb.CaseStatus{
+				CaseId: c.id,
+				State:  caseState(jobStatus.State),
+				Error:  jobStatus.Error,
+			}}})
+			if err != nil {
+				return err
+			}
+		}
+
+		report, finished := j.result()
+		if finished {
+			if report == nil {
+				return nil
+			}
+			caseReport, err := newCaseReport(c.id, report)
+			if err != nil {
+				return err
+			}
+			return stream.Send(&pb.CaseEvent{Event: &pb.CaseEvent_Report{Report: caseReport}})
+		}
+
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (js *JudgeService) lookup(caseID string) (*judgeCase, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	c, ok := js.cases[caseID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Case %s does not exist", caseID)
+	}
+	return c, nil
+}
+
+// notify wakes up the watchers of the case. The lock of the service has to be held
+func (c *judgeCase) notify() {
+	close(c.updated)
+	c.updated = make(chan struct{})
+}
+
+// status returns the status of the case. The lock of the service has to be held
+func (c *judgeCase) status() *pb.CaseStatus {
+	if c.job != nil {
+		jobStatus := c.job.status()
+		return &pb.CaseStatus{CaseId: c.id, State: caseState(jobStatus.State), Error: jobStatus.Error}
+	}
+	if c.err != nil {
+		return &pb.CaseStatus{CaseId: c.id, State: pb.CaseState_FAILED, Error: c.err.Error()}
+	}
+	caseStatus := &pb.CaseStatus{CaseId: c.id, State: pb.CaseState_AWAITING_BLOCKS}
+	for _, peer := range c.peers {
+		if !c.uploaded[peer] {
+			caseStatus.PendingPeers = append(caseStatus.PendingPeers, peer)
+		}
+	}
+	return caseStatus
+}
+
+func caseState(state JobState) pb.CaseState {
+	switch state {
+	case StateQueued:
+		return pb.CaseState_QUEUED
+	case StateRunning:
+		return pb.CaseState_RUNNING
+	case StateSucceeded:
+		return pb.CaseState_SUCCEEDED
+	case StateCancelled:
+		return pb.CaseState_CANCELLED
+	}
+	return pb.CaseState_FAILED
+}
+
+// newCaseReport converts the report of a job. Bundles are referenced by their name, the certificate is included
+func newCaseReport(id string, report *judge.Report) (*pb.CaseReport, error) {
+	caseReport := &pb.CaseReport{
+		CaseId:        id,
+		JudgeVersion:  report.JudgeVersion,
+		ConsensusType: report.ConsensusType,
+		ChannelName:   report.ChannelName,
+		Consistent:    report.Consistent,
+		Check:         report.Check,
+	}
+	for _, ledger := range report.Ledgers {
+		caseReport.Ledgers = append(caseReport.Ledgers, &pb.Ledger{Identity: ledger.Identity, Height: uint64(ledger.Height)})
+	}
+	for _, phase := range report.Phases {
+		caseReport.Phases = append(caseReport.Phases, &pb.Phase{Check: phase.Check, Description: phase.Description, Passed: phase.Passed})
+	}
+	for _, v := range report.Verdicts {
+		verdict := &pb.Verdict{
+			Check:      v.Check,
+			Message:    v.Message,
+			Identity:   v.Identity,
+			Type:       pb.VerdictType(v.Type),
+			Category:   v.Category,
+			Evaluation: v.Evaluation,
+		}
+		if v.Bundle != "" {
+			verdict.Bundle = filepath.Base(v.Bundle)
+		}
+		for _, ev := range v.Evidence {
+			verdict.Evidence = append(verdict.Evidence, &pb.Evidence{Ledger: ev.Ledger, Blocks: ev.Blocks})
+		}
+		caseReport.Verdicts = append(caseReport.Verdicts, verdict)
+	}
+	if report.Certificate != "" {
+		certificate, err := ioutil.ReadFile(report.Certificate)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Unable to read the certificate: %s", err)
+		}
+		caseReport.Certificate = certificate
+	}
+	return caseReport, nil
+}
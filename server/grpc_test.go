@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/evidence"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	pb "github.com/hyperledger/fabric_judge/protos/judge"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcService serves the gRPC API on a local port. It returns a client, the fixture, whose Kafka key is trusted by the service, and its key id
+func grpcService(t *testing.T) (pb.JudgeClient, *ledgertest.KafkaFixture, string, func()) {
+	dir, err := ioutil.TempDir("", "grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{KeyDir: filepath.Join(dir, "keys")}
+	err = os.MkdirAll(config.KeyDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := ledgertest.NewKafkaFixture(t, config.KeyDir)
+	keyBytes, err := ioutil.ReadFile(f.KafkaPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, cleanup := newTestServer(t, config)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(MaxGrpcMessageBytes), grpc.MaxSendMsgSize(MaxGrpcMessageBytes))
+	pb.RegisterJudgeServer(grpcServer, NewJudgeService(s))
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pb.NewJudgeClient(conn), f, evidence.KafkaKeyID(keyBytes), func() {
+		conn.Close()
+		grpcServer.Stop()
+		cleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+// upload streams the blocks as the ledger of the peer
+func upload(t *testing.T, client pb.JudgeClient, caseID string, peer string, blocks []*cb.Block) (*pb.LedgerReceipt, error) {
+	stream, err := client.StreamBlocks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, block := range blocks {
+		upload := &pb.BlockUpload{Block: block}
+		if k == 0 {
+			upload.CaseId, upload.Peer = caseID, peer
+		}
+		err = stream.Send(upload)
+		if err != nil {
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// watch follows the case until its report is sent
+func watch(t *testing.T, client pb.JudgeClient, caseID string) *pb.CaseReport {
+	stream, err := client.WatchCase(context.Background(), &pb.CaseID{CaseId: caseID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			t.Fatalf("Case %s finished without report", caseID)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report := event.GetReport(); report != nil {
+			return report
+		}
+	}
+}
+
+// submitCase submits a case of peerA and peerB and uploads their ledgers
+func submitCase(t *testing.T, client pb.JudgeClient, kafkaKeyID string, blocksA []*cb.Block, blocksB []*cb.Block) *pb.CaseReport {
+	submitted, err := client.SubmitCase(context.Background(), &pb.CaseRequest{Channel: ledgertest.ChannelName, Peers: []string{"peerA", "peerB"}, KafkaKeyId: kafkaKeyID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submitted.State != pb.CaseState_AWAITING_BLOCKS || len(submitted.PendingPeers) != 2 {
+		t.Fatalf("Expected the case to await both ledgers, got %s %v", submitted.State, submitted.PendingPeers)
+	}
+	for peer, blocks := range map[string][]*cb.Block{"peerA": blocksA, "peerB": blocksB} {
+		receipt, err := upload(t, client, submitted.CaseId, peer, blocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.Height != uint64(len(blocks)) {
+			t.Fatalf("Expected a receipt of %d blocks, got %d", len(blocks), receipt.Height)
+		}
+	}
+	return watch(t, client, submitted.CaseId)
+}
+
+func TestJudgeServiceJudgesUploadedLedgers(t *testing.T) {
+	client, f, kafkaKeyID, cleanup := grpcService(t)
+	defer cleanup()
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+
+	report := submitCase(t, client, kafkaKeyID, blocks, blocks)
+	if !report.Consistent || len(report.Ledgers) != 2 || len(report.Verdicts) != 0 {
+		t.Fatalf("Expected the consistent ledgers to pass, got %v", report.Verdicts)
+	}
+
+	// Kafka signed different envelopes at offset 2 for peerB
+	forged := append(blocks[:2:2], f.Block(blocks[1], []ledgertest.Message{{Offset: 2, Data: "forged"}, {Offset: 3}}, 0, 0))
+	report = submitCase(t, client, kafkaKeyID, blocks, forged)
+	if report.Consistent || len(report.Verdicts) == 0 || report.Verdicts[0].Identity != "Kafka Cluster" {
+		t.Fatalf("Expected Kafka to be blamed for the equivocation, got %v", report.Verdicts)
+	}
+}
+
+func TestJudgeServiceRejectsBadUploads(t *testing.T) {
+	client, f, kafkaKeyID, cleanup := grpcService(t)
+	defer cleanup()
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+
+	_, err := client.SubmitCase(context.Background(), &pb.CaseRequest{Channel: ledgertest.ChannelName, Peers: []string{"peerA"}, KafkaKeyId: kafkaKeyID})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected the case of a single peer to be rejected, got: %v", err)
+	}
+
+	submitted, err := client.SubmitCase(context.Background(), &pb.CaseRequest{Channel: ledgertest.ChannelName, Peers: []string{"peerA", "peerB"}, KafkaKeyId: kafkaKeyID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = upload(t, client, submitted.CaseId, "peerC", blocks)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected the ledger of a peer outside of the case to be rejected, got: %v", err)
+	}
+	_, err = upload(t, client, submitted.CaseId, "peerA", []*cb.Block{blocks[0], blocks[2]})
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != "Expected block 1, but received block 2" {
+		t.Fatalf("Expected the skipped block to be rejected, got: %v", err)
+	}
+	_, err = client.GetVerdicts(context.Background(), &pb.CaseID{CaseId: submitted.CaseId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected the verdicts of the case awaiting its ledgers to be unavailable, got: %v", err)
+	}
+
+	// the rejected ledger can be uploaded again
+	if _, err = upload(t, client, submitted.CaseId, "peerA", blocks); err != nil {
+		t.Fatalf("Expected the ledger to be uploaded after the rejected attempt, got: %s", err)
+	}
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/judge"
+)
+
+// JobState is the state of a job in the worker pool
+type JobState string
+
+const (
+	StateQueued    JobState = "queued"
+	StateRunning   JobState = "running"
+	StateSucceeded JobState = "succeeded"
+	StateFailed    JobState = "failed"
+	StateCancelled JobState = "cancelled"
+)
+
+// JobStatus is the status of a job, which is returned to clients polling it
+type JobStatus struct {
+	ID          string   `json:"id"`
+	State       JobState `json:"state"`
+	Error       string   `json:"error,omitempty"`
+	SubmittedAt string   `json:"submitted_at"`
+	StartedAt   string   `json:"started_at,omitempty"`
+	FinishedAt  string   `json:"finished_at,omitempty"`
+	Consistent  *bool    `json:"consistent,omitempty"`
+	Progress    []string `json:"progress"`
+}
+
+// job is a single judge run, which is submitted to the service
+type job struct {
+	mu     sync.Mutex
+	id     string
+	dir    string
+	run    *judge.Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	// checkpoints is held while the job runs, if it reads and writes checkpoints, which other jobs share
+	checkpoints *sync.Mutex
+
+	state       JobState
+	err         error
+	submittedAt time.Time
+	startedAt   time.Time
+	finishedAt  time.Time
+	progress    []string
+	report      *judge.Report
+	// updated is closed and replaced on every change of the job, which wakes up all watchers
+	updated chan struct{}
+}
+
+func newJob(ctx context.Context, id string, dir string, run *judge.Job) *job {
+	j := &job{
+		id:          id,
+		dir:         dir,
+		run:         run,
+		state:       StateQueued,
+		submittedAt: time.Now().UTC(),
+		progress:    make([]string, 0),
+		updated:     make(chan struct{}),
+	}
+	j.ctx, j.cancel = context.WithCancel(ctx)
+	run.Progress = j.addProgress
+	return j
+}
+
+func (j *job) addProgress(message string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = append(j.progress, strings.TrimSpace(message))
+	j.notify()
+}
+
+// notify wakes up the watchers of the job. The lock of the job has to be held
+func (j *job) notify() {
+	close(j.updated)
+	j.updated = make(chan struct{})
+}
+
+// start marks the job as running. It returns false, if the job was cancelled while it was queued
+func (j *job) start() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state != StateQueued {
+		return false
+	}
+	if j.ctx.Err() != nil {
+		j.state = StateCancelled
+		j.finishedAt = time.Now().UTC()
+		j.notify()
+		return false
+	}
+	j.state = StateRunning
+	j.startedAt = time.Now().UTC()
+	j.notify()
+	return true
+}
+
+func (j *job) finish(report *judge.Report, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.finishedAt = time.Now().UTC()
+	switch {
+	case err != nil && j.ctx.Err() != nil:
+		j.state = StateCancelled
+	case err != nil:
+		j.state = StateFailed
+		j.err = err
+	default:
+		j.state = StateSucceeded
+		j.report = report
+	}
+	j.notify()
+}
+
+// stop cancels the job. A queued job is cancelled immediately, a running job is cancelled before its next phase
+func (j *job) stop() {
+	j.cancel()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state == StateQueued {
+		j.state = StateCancelled
+		j.finishedAt = time.Now().UTC()
+		j.notify()
+	}
+}
+
+func (j *job) status() *JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.statusLocked()
+}
+
+// changes returns the status of the job together with the progress messages after the given number of seen messages,
+// and a channel which is closed on the next change of the job
+func (j *job) changes(seen int) (*JobStatus, []string, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.statusLocked()
+	if seen > len(status.Progress) {
+		seen = len(status.Progress)
+	}
+	return status, status.Progress[seen:], j.updated
+}
+
+func (j *job) statusLocked() *JobStatus {
+	status := &JobStatus{
+		ID:          j.id,
+		State:       j.state,
+		SubmittedAt: formatTime(j.submittedAt),
+		StartedAt:   formatTime(j.startedAt),
+		FinishedAt:  formatTime(j.finishedAt),
+		Progress:    append(make([]string, 0, len(j.progress)), j.progress...),
+	}
+	if j.err != nil {
+		status.Error = j.err.Error()
+	}
+	if j.report != nil {
+		status.Consistent = &j.report.Consistent
+	}
+	return status
+}
+
+// result returns the report of a finished job and whether the job has finished
+func (j *job) result() (*judge.Report, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.report, j.state != StateQueued && j.state != StateRunning
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// worker runs the queued jobs until the queue is closed
+func (s *Server) worker() {
+	defer s.workers.Done()
+	for j := range s.queue {
+		if !j.start() {
+			continue
+		}
+		runJob(j)
+	}
+}
+
+// runJob runs the judge on the ledgers of the job. A panic of the judge only fails the job instead of the service
+func runJob(j *job) {
+	defer func() {
+		if r := recover(); r != nil {
+			j.finish(nil, fmt.Errorf("Judge run failed: %v", r))
+		}
+	}()
+	if j.checkpoints != nil {
+		j.checkpoints.Lock()
+		defer j.checkpoints.Unlock()
+	}
+	report, err := judge.Run(j.ctx, j.run)
+	j.finish(report, err)
+}
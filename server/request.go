@@ -0,0 +1,264 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/backend"
+	"github.com/hyperledger/fabric_judge/evidence"
+	"github.com/hyperledger/fabric_judge/judge"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+// JobRequest is the body of a job submission. Keys are referenced by their key id and resolved in the key directory of the service
+type JobRequest struct {
+	Channel           string           `json:"channel"`
+	Ledgers           []*LedgerRequest `json:"ledgers"`
+	KafkaKeyID        string           `json:"kafka_key_id,omitempty"`
+	MaxBatchSize      int              `json:"max_batch_size,omitempty"`
+	PreferredMaxBytes int              `json:"preferred_max_bytes,omitempty"`
+	AbsoluteMaxBytes  int              `json:"absolute_max_bytes,omitempty"`
+	OrdererVersion    string           `json:"orderer_version,omitempty"`
+	BatchTimeout      string           `json:"batch_timeout,omitempty"`
+	TimingTolerance   string           `json:"timing_tolerance,omitempty"`
+	Redact            bool             `json:"redact,omitempty"`
+	RecipientKeyIDs   []string         `json:"recipient_key_ids,omitempty"`
+}
+
+// LedgerRequest is the ledger of a single peer. Either the path of its block directory on the host of the service is given,
+// or the name of the uploaded tar archive, which contains the block files
+type LedgerRequest struct {
+	Identity string `json:"identity"`
+	Path     string `json:"path,omitempty"`
+	Archive  string `json:"archive,omitempty"`
+}
+
+// channelNamePattern and maxChannelNameLength are the restrictions of Fabric on channel names. The channel name is part
+// of the names of the block files, thus it must not contain path separators
+var channelNamePattern = regexp.MustCompile(`^[a-z][a-z0-9.-]*$`)
+
+const maxChannelNameLength = 249
+
+// archiveSource returns the content of an uploaded archive by its name
+type archiveSource func(name string) ([]byte, error)
+
+// newJudgeJob validates the parameters of the request and converts them into a judge run, whose files are kept in the given directory.
+// The ledgers of the run are added afterwards
+func (s *Server) newJudgeJob(request *JobRequest, dir string) (*judge.Job, error) {
+	if request.Channel == "" {
+		return nil, fmt.Errorf("Job does not name a channel")
+	}
+	if len(request.Channel) > maxChannelNameLength || !channelNamePattern.MatchString(request.Channel) {
+		return nil, fmt.Errorf("Invalid channel name %q, expected at most %d characters matching %s", request.Channel, maxChannelNameLength, channelNamePattern)
+	}
+
+	if request.MaxBatchSize < 0 || request.PreferredMaxBytes < 0 || request.AbsoluteMaxBytes < 0 {
+		return nil, fmt.Errorf("BatchSize parameters must not be negative, missing parameters are read from the genesis config")
+	}
+
+	options := &backend.Options{
+		ChannelName:       request.Channel,
+		MaxBatchSize:      request.MaxBatchSize,
+		PreferredMaxBytes: request.PreferredMaxBytes,
+		AbsoluteMaxBytes:  request.AbsoluteMaxBytes,
+		OrdererVersion:    request.OrdererVersion,
+		TimingTolerance:   500 * time.Millisecond,
+	}
+	if options.OrdererVersion == "" {
+		options.OrdererVersion = validator.DefaultOrdererVersion
+	}
+	_, err := validator.SizeAccountingOfVersion(options.OrdererVersion)
+	if err != nil {
+		return nil, err
+	}
+	if request.BatchTimeout != "" {
+		options.BatchTimeout, err = time.ParseDuration(request.BatchTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid batch timeout: %s", err)
+		}
+	}
+	if request.TimingTolerance != "" {
+		options.TimingTolerance, err = time.ParseDuration(request.TimingTolerance)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid timing tolerance: %s", err)
+		}
+	}
+	if request.KafkaKeyID != "" {
+		options.KafkaPublicKey, err = s.keyPath(request.KafkaKeyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	run := &judge.Job{
+		Options:     options,
+		EvidenceDir: filepath.Join(dir, "evidence"),
+		Redact:      request.Redact,
+	}
+	if s.config.SigningKey != "" {
+		run.SigningKey = s.config.SigningKey
+		run.CertificatePath = filepath.Join(dir, "verdict_certificate.json")
+	}
+	for _, keyID := range request.RecipientKeyIDs {
+		path, err := s.keyPath(keyID)
+		if err != nil {
+			return nil, err
+		}
+		run.Recipients = append(run.Recipients, path)
+	}
+
+	return run, nil
+}
+
+// addLedgers adds the requested ledgers to the run. Uploaded archives are unpacked into the directory of the job.
+// Only runs on ledgers below LedgerRoot use checkpoints, since uploaded ledgers are not trusted to be the ledgers of the named peers
+func (s *Server) addLedgers(run *judge.Job, ledgers []*LedgerRequest, dir string, archives archiveSource) error {
+	if len(ledgers) < 2 {
+		return fmt.Errorf("Job needs the ledgers of at least two peers, but %d were given", len(ledgers))
+	}
+	uploaded := false
+	for k, ledger := range ledgers {
+		if ledger.Identity == "" {
+			return fmt.Errorf("Ledger %d does not name the identity of its peer", k)
+		}
+		blockDir, err := s.blockDir(ledger, ledgerDir(dir, k), archives)
+		if err != nil {
+			return err
+		}
+		uploaded = uploaded || ledger.Path == ""
+		run.Ledgers = append(run.Ledgers, &judge.LedgerSource{Identity: ledger.Identity, BlockDir: blockDir})
+	}
+	err := requireKafkaKey(run)
+	if err != nil {
+		return err
+	}
+	if s.config.CheckpointDir != "" && !uploaded {
+		run.CheckpointDir = filepath.Join(s.config.CheckpointDir, checkpointScope(run.Ledgers))
+	}
+	return nil
+}
+
+// requireKafkaKey rejects runs on Kafka channels, which do not reference the public key of the Kafka Cluster.
+// The consensus type is read from the genesis block of the first ledger
+func requireKafkaKey(run *judge.Job) error {
+	if run.Options.KafkaPublicKey != "" {
+		return nil
+	}
+	consensusType, err := judge.ConsensusType(run.Ledgers[0], run.Options.ChannelName)
+	if err != nil {
+		return fmt.Errorf("Unable to read the genesis block of %s: %s", run.Ledgers[0].Identity, err)
+	}
+	if consensusType == "kafka" {
+		return fmt.Errorf("Channel %s is ordered by Kafka, thus the job needs the key id of the Kafka Cluster", run.Options.ChannelName)
+	}
+	return nil
+}
+
+// checkpointScope identifies the ledgers of a run by the identities and block directories of the peers.
+// Runs on the same ledgers share their checkpoints, whereas runs naming the same peers for other ledgers never resume from them
+func checkpointScope(ledgers []*judge.LedgerSource) string {
+	h := sha256.New()
+	for _, ledger := range ledgers {
+		h.Write([]byte(ledger.Identity + "\x00" + filepath.Clean(ledger.BlockDir) + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ledgerDir returns the directory of the job, into which the k-th ledger is uploaded
+func ledgerDir(dir string, k int) string {
+	return filepath.Join(dir, fmt.Sprintf("ledger-%d", k))
+}
+
+// blockDir returns the block directory of the ledger. Uploaded archives are unpacked into the given directory
+func (s *Server) blockDir(ledger *LedgerRequest, dir string, archives archiveSource) (string, error) {
+	switch {
+	case ledger.Path != "" && ledger.Archive != "":
+		return "", fmt.Errorf("Ledger of %s is given by a path and an archive", ledger.Identity)
+	case ledger.Path != "":
+		if s.config.LedgerRoot == "" {
+			return "", fmt.Errorf("Service does not accept ledger paths, the ledger of %s has to be uploaded", ledger.Identity)
+		}
+		path := filepath.Join(s.config.LedgerRoot, filepath.FromSlash(ledger.Path))
+		if !strings.HasPrefix(path, filepath.Clean(s.config.LedgerRoot)+string(filepath.Separator)) {
+			return "", fmt.Errorf("Ledger path %s of %s is outside of the ledger root", ledger.Path, ledger.Identity)
+		}
+		return path, nil
+	case ledger.Archive != "":
+		archive, err := archives(ledger.Archive)
+		if err != nil {
+			return "", err
+		}
+		// block archives are commonly compressed, which is detected by the gzip magic number
+		if len(archive) > 2 && archive[0] == 0x1f && archive[1] == 0x8b {
+			reader, err := gzip.NewReader(bytes.NewReader(archive))
+			if err != nil {
+				return "", fmt.Errorf("Unable to decompress archive %s: %s", ledger.Archive, err)
+			}
+			// the decompressed size is not bounded by the size of the upload, thus it is limited separately
+			var limited io.Reader = reader
+			if s.config.MaxArchiveBytes > 0 {
+				limited = io.LimitReader(reader, s.config.MaxArchiveBytes+1)
+			}
+			archive, err = ioutil.ReadAll(limited)
+			if err != nil {
+				return "", fmt.Errorf("Unable to decompress archive %s: %s", ledger.Archive, err)
+			}
+			if s.config.MaxArchiveBytes > 0 && int64(len(archive)) > s.config.MaxArchiveBytes {
+				return "", fmt.Errorf("Archive %s exceeds %d bytes after its decompression", ledger.Archive, s.config.MaxArchiveBytes)
+			}
+		}
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return "", err
+		}
+		err = evidence.ExtractArchive(archive, dir)
+		if err != nil {
+			return "", fmt.Errorf("Unable to unpack archive %s: %s", ledger.Archive, err)
+		}
+		return dir, nil
+	}
+	return "", fmt.Errorf("Ledger of %s is given neither by a path nor by an archive", ledger.Identity)
+}
+
+// loadKeys indexes the raw key files of the key directory by their Kafka and recipient key ids
+func loadKeys(dir string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if dir == "" {
+		return keys, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		keyBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys[evidence.KafkaKeyID(keyBytes)] = path
+		keys[evidence.RecipientKeyID(keyBytes)] = path
+	}
+	return keys, nil
+}
+
+func (s *Server) keyPath(keyID string) (string, error) {
+	path, ok := s.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("Key %s is unknown to the service", keyID)
+	}
+	return path, nil
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// newTestServer creates a service with a single worker, whose jobs are kept in a temporary directory
+func newTestServer(t *testing.T, config *Config) (*Server, func()) {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.WorkDir = dir
+	config.Workers = 1
+	config.QueueSize = 4
+	s, err := NewServer(config)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNewJudgeJobValidatesChannelName(t *testing.T) {
+	s, cleanup := newTestServer(t, &Config{})
+	defer cleanup()
+
+	for _, channel := range []string{"mychannel", "my-channel.2"} {
+		_, err := s.newJudgeJob(&JobRequest{Channel: channel}, s.config.WorkDir)
+		if err != nil {
+			t.Errorf("Expected channel %q to be accepted, got: %s", channel, err)
+		}
+	}
+	for _, channel := range []string{"", "../../x", "a/b", "MyChannel", "1channel", string(make([]byte, 250))} {
+		_, err := s.newJudgeJob(&JobRequest{Channel: channel}, s.config.WorkDir)
+		if err == nil {
+			t.Errorf("Expected channel %q to be rejected", channel)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric_judge/evidence"
+	"github.com/hyperledger/fabric_judge/judge"
+)
+
+// maxMemory is the part of a multipart upload, which is kept in memory. Larger archives are buffered in temporary files
+const maxMemory = 32 << 20
+
+// Config contains the parameters of the judge service
+type Config struct {
+	// WorkDir contains a directory per job with its uploaded ledgers, evidence bundles and certificate
+	WorkDir string
+	// Workers is the number of jobs, which are run concurrently
+	Workers int
+	// QueueSize is the number of jobs, which can wait for a worker. Further submissions are rejected
+	QueueSize int
+	// KeyDir contains the raw Kafka and recipient public keys, which jobs reference by their key id
+	KeyDir string
+	// SigningKey is the Ed25519 secret key of the judge. If it is set, every job writes a signed verdict certificate
+	SigningKey string
+	// LedgerRoot is the directory, below which jobs may reference ledgers by their path. If it is empty, ledgers have to be uploaded
+	LedgerRoot string
+	// MaxUploadBytes limits the size of a job submission
+	MaxUploadBytes int64
+	// MaxArchiveBytes limits the size of a single ledger archive after its decompression
+	MaxArchiveBytes int64
+	// CheckpointDir contains the checkpoints of the jobs on ledgers referenced below LedgerRoot, in a directory per set of ledgers.
+	// If it is set, such a job resumes from the checkpoints of the previous job on the same ledgers. Jobs on uploaded ledgers never use checkpoints
+	CheckpointDir string
+}
+
+// Server runs submitted judge jobs in a bounded pool of workers and serves their status, reports and evidence bundles via HTTP
+type Server struct {
+	config  *Config
+	keys    map[string]string
+	queue   chan *job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	nextID int
+	closed bool
+	// checkpointLocks serializes the jobs sharing a checkpoint directory
+	checkpointLocks map[string]*sync.Mutex
+}
+
+// NewServer creates the service and starts its workers
+func NewServer(config *Config) (*Server, error) {
+	if config.Workers < 1 || config.QueueSize < 0 {
+		return nil, fmt.Errorf("Service needs at least one worker and a non-negative queue size")
+	}
+	err := os.MkdirAll(config.WorkDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := loadKeys(config.KeyDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the key directory: %s", err)
+	}
+
+	s := &Server{
+		config:          config,
+		keys:            keys,
+		queue:           make(chan *job, config.QueueSize),
+		jobs:            make(map[string]*job),
+		checkpointLocks: make(map[string]*sync.Mutex),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for i := 0; i < config.Workers; i++ {
+		s.workers.Add(1)
+		go s.worker()
+	}
+	return s, nil
+}
+
+// Close rejects further submissions, cancels all jobs and waits until the workers have stopped
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	for _, j := range s.jobs {
+		j.stop()
+	}
+	close(s.queue)
+	s.mu.Unlock()
+
+	s.cancel()
+	s.workers.Wait()
+}
+
+// Handler returns the HTTP API of the service:
+//
+//	POST   /jobs                        submits a job, either as JSON JobRequest or as multipart form with the
+//	                                    JobRequest in the field "job" and the ledger archives as files
+//	GET    /jobs                        lists the status of all jobs
+//	GET    /jobs/{id}                   returns the status of a job
+//	DELETE /jobs/{id}                   cancels a job
+//	GET    /jobs/{id}/report            returns the report of a finished job
+//	GET    /jobs/{id}/certificate       downloads the signed verdict certificate of a finished job
+//	GET    /jobs/{id}/bundles/{name}    downloads an evidence bundle as tar archive, or as encrypted container
+//	GET    /keys                        lists the key ids known to the service
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", s.handleJobs)
+	mux.HandleFunc("/jobs/", s.handleJob)
+	mux.HandleFunc("/keys", s.handleKeys)
+	return mux
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.submit(w, r)
+	case http.MethodGet:
+		s.mu.Lock()
+		statuses := make([]*JobStatus, 0, len(s.jobs))
+		for _, j := range s.jobs {
+			statuses = append(statuses, j.status())
+		}
+		s.mu.Unlock()
+		sort.Slice(statuses, func(a, b int) bool { return statuses[a].ID < statuses[b].ID })
+		writeJSON(w, http.StatusOK, statuses)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed", r.Method))
+	}
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+	j, ok := s.lookup(parts[0])
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Job %s does not exist", parts[0]))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, j.status())
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		j.stop()
+		writeJSON(w, http.StatusAccepted, j.status())
+	case len(parts) == 2 && parts[1] == "report" && r.Method == http.MethodGet:
+		report, ok := s.finishedReport(w, j)
+		if ok {
+			writeJSON(w, http.StatusOK, newReportView(j.id, report))
+		}
+	case len(parts) == 2 && parts[1] == "certificate" && r.Method == http.MethodGet:
+		report, ok := s.finishedReport(w, j)
+		if !ok {
+			return
+		}
+		if report.Certificate == "" {
+			writeError(w, http.StatusNotFound, fmt.Errorf("Job %s did not write a certificate", j.id))
+			return
+		}
+		http.ServeFile(w, r, report.Certificate)
+	case len(parts) == 3 && parts[1] == "bundles" && r.Method == http.MethodGet:
+		report, ok := s.finishedReport(w, j)
+		if ok {
+			s.serveBundle(w, r, report, parts[2])
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown resource %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed", r.Method))
+		return
+	}
+	keyIDs := make([]string, 0, len(s.keys))
+	for keyID := range s.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+	writeJSON(w, http.StatusOK, keyIDs)
+}
+
+// submit creates a job from the request and queues it. The job is rejected, if the queue is full
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	if s.config.MaxUploadBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxUploadBytes)
+	}
+
+	request := &JobRequest{}
+	archives := func(name string) ([]byte, error) {
+		return nil, fmt.Errorf("Archive %s was not uploaded", name)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		err := r.ParseMultipartForm(maxMemory)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Unable to parse the upload: %s", err))
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+		err = json.Unmarshal([]byte(r.FormValue("job")), request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Unable to parse the job: %s", err))
+			return
+		}
+		archives = func(name string) ([]byte, error) {
+			file, _, err := r.FormFile(name)
+			if err != nil {
+				return nil, fmt.Errorf("Archive %s was not uploaded", name)
+			}
+			defer file.Close()
+			return ioutil.ReadAll(file)
+		}
+	} else {
+		err := json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Unable to parse the job: %s", err))
+			return
+		}
+	}
+
+	id := s.newJobID()
+	dir := filepath.Join(s.config.WorkDir, id)
+	run, err := s.newJudgeJob(request, dir)
+	if err == nil {
+		err = s.addLedgers(run, request.Ledgers, dir, archives)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	j, err := s.enqueue(id, dir, run)
+	if err != nil {
+		os.RemoveAll(dir)
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, j.status())
+}
+
+func (s *Server) newJobID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return fmt.Sprintf("job-%06d", s.nextID)
+}
+
+// enqueue creates the job for the run and queues it. It fails, if the queue is full or the service is shutting down
+func (s *Server) enqueue(id string, dir string, run *judge.Job) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("Service is shutting down")
+	}
+	j := newJob(s.ctx, id, dir, run)
+	if run.CheckpointDir != "" {
+		if s.checkpointLocks[run.CheckpointDir] == nil {
+			s.checkpointLocks[run.CheckpointDir] = &sync.Mutex{}
+		}
+		j.checkpoints = s.checkpointLocks[run.CheckpointDir]
+	}
+	select {
+	case s.queue <- j:
+		s.jobs[id] = j
+		return j, nil
+	default:
+		return nil, fmt.Errorf("Job queue is full")
+	}
+}
+
+// lookup returns the job with the given id
+func (s *Server) lookup(id string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	return j, ok
+}
+
+// finishedReport returns the report of the job, or writes an error if the job has not finished successfully
+func (s *Server) finishedReport(w http.ResponseWriter, j *job) (*judge.Report, bool) {
+	report, finished := j.result()
+	if !finished {
+		writeError(w, http.StatusConflict, fmt.Errorf("Job %s has not finished yet", j.id))
+		return nil, false
+	}
+	if report == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Job %s did not produce a report", j.id))
+		return nil, false
+	}
+	return report, true
+}
+
+// reportView is the report of a job together with the URLs, from which its evidence can be downloaded
+type reportView struct {
+	ID string `json:"id"`
+	*judge.Report
+	BundleURLs     []string `json:"bundle_urls"`
+	CertificateURL string   `json:"certificate_url,omitempty"`
+}
+
+func newReportView(id string, report *judge.Report) *reportView {
+	view := &reportView{ID: id, Report: report, BundleURLs: make([]string, 0)}
+	for _, v := range report.Verdicts {
+		if v.Bundle != "" {
+			view.BundleURLs = append(view.BundleURLs, "/jobs/"+id+"/bundles/"+filepath.Base(v.Bundle))
+		}
+	}
+	if report.Certificate != "" {
+		view.CertificateURL = "/jobs/" + id + "/certificate"
+	}
+	return view
+}
+
+// serveBundle sends the evidence bundle with the given name. Plaintext bundles are sent as tar archive
+func (s *Server) serveBundle(w http.ResponseWriter, r *http.Request, report *judge.Report, name string) {
+	for _, v := range report.Verdicts {
+		if v.Bundle == "" || filepath.Base(v.Bundle) != name {
+			continue
+		}
+		if strings.HasSuffix(v.Bundle, evidence.SealedExtension) {
+			http.ServeFile(w, r, v.Bundle)
+			return
+		}
+		archive, err := evidence.ArchiveDir(v.Bundle)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".tar"))
+		w.Write(archive)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("Bundle %s does not exist", name))
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/evidence"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+// kafkaService serves a service, whose ledger root contains the consistent ledgers of peerA and peerB in the directories a and b.
+// It returns the key id of the Kafka Cluster
+func kafkaService(t *testing.T, config *Config) (*httptest.Server, string, func()) {
+	dir, err := ioutil.TempDir("", "service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.KeyDir = filepath.Join(dir, "keys")
+	config.LedgerRoot = filepath.Join(dir, "ledgers")
+	err = os.MkdirAll(config.KeyDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := ledgertest.NewKafkaFixture(t, config.KeyDir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	ledgertest.WriteLedger(t, filepath.Join(config.LedgerRoot, "a"), blocks)
+	ledgertest.WriteLedger(t, filepath.Join(config.LedgerRoot, "b"), blocks)
+	keyBytes, err := ioutil.ReadFile(f.KafkaPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, cleanup := newTestServer(t, config)
+	ts := httptest.NewServer(s.Handler())
+	return ts, evidence.KafkaKeyID(keyBytes), func() {
+		ts.Close()
+		cleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+// decode reads the JSON body of the response and checks its status code
+func decode(t *testing.T, response *http.Response, err error, status int, value interface{}) {
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != status {
+		t.Fatalf("Expected status %d, got %d: %s", status, response.StatusCode, body)
+	}
+	err = json.Unmarshal(body, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func submitJSON(t *testing.T, ts *httptest.Server, request *JobRequest, status int) *JobStatus {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader(requestBytes))
+	submitted := &JobStatus{}
+	decode(t, response, err, status, submitted)
+	return submitted
+}
+
+// pollJob polls the status of the job until it has finished
+func pollJob(t *testing.T, ts *httptest.Server, id string) *JobStatus {
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		response, err := http.Get(ts.URL + "/jobs/" + id)
+		polled := &JobStatus{}
+		decode(t, response, err, http.StatusOK, polled)
+		if polled.State != StateQueued && polled.State != StateRunning {
+			return polled
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Job %s did not finish", id)
+	return nil
+}
+
+func TestSubmitAndPollJob(t *testing.T) {
+	ts, kafkaKeyID, cleanup := kafkaService(t, &Config{})
+	defer cleanup()
+
+	// the BatchSize is not given, thus it is read from the genesis config
+	submitted := submitJSON(t, ts, &JobRequest{
+		Channel:    ledgertest.ChannelName,
+		KafkaKeyID: kafkaKeyID,
+		Ledgers:    []*LedgerRequest{{Identity: "peerA", Path: "a"}, {Identity: "peerB", Path: "b"}},
+	}, http.StatusAccepted)
+
+	polled := pollJob(t, ts, submitted.ID)
+	if polled.State != StateSucceeded || polled.Consistent == nil || !*polled.Consistent {
+		t.Fatalf("Expected the job to succeed on consistent ledgers, got %s: %s %v", polled.State, polled.Error, polled.Progress)
+	}
+	if !strings.Contains(strings.Join(polled.Progress, "\n"), "MaxBatchSize is not given, using 2 of the genesis config") {
+		t.Fatalf("Expected the BatchSize to be read from the genesis config, got %v", polled.Progress)
+	}
+
+	response, err := http.Get(ts.URL + "/jobs/" + submitted.ID + "/report")
+	report := &reportView{}
+	decode(t, response, err, http.StatusOK, report)
+	if report.ID != submitted.ID || !report.Consistent || len(report.Ledgers) != 2 {
+		t.Fatalf("Expected the report of the consistent ledgers, got %+v", report.Report)
+	}
+}
+
+func TestSubmitRejectsBadParameters(t *testing.T) {
+	ts, kafkaKeyID, cleanup := kafkaService(t, &Config{})
+	defer cleanup()
+
+	ledgers := []*LedgerRequest{{Identity: "peerA", Path: "a"}, {Identity: "peerB", Path: "b"}}
+	for name, request := range map[string]*JobRequest{
+		"channel outside of the work directory": {Channel: "../../x", KafkaKeyID: kafkaKeyID, Ledgers: ledgers},
+		"negative MaxBatchSize":                 {Channel: ledgertest.ChannelName, KafkaKeyID: kafkaKeyID, MaxBatchSize: -1, Ledgers: ledgers},
+		"unknown orderer version":               {Channel: ledgertest.ChannelName, KafkaKeyID: kafkaKeyID, OrdererVersion: "0.1", Ledgers: ledgers},
+		"invalid batch timeout":                 {Channel: ledgertest.ChannelName, KafkaKeyID: kafkaKeyID, BatchTimeout: "2", Ledgers: ledgers},
+		"unknown key id":                        {Channel: ledgertest.ChannelName, KafkaKeyID: "unknown", Ledgers: ledgers},
+		"Kafka channel without key":             {Channel: ledgertest.ChannelName, Ledgers: ledgers},
+		"single ledger":                         {Channel: ledgertest.ChannelName, KafkaKeyID: kafkaKeyID, Ledgers: ledgers[:1]},
+		"ledger outside of the ledger root":     {Channel: ledgertest.ChannelName, KafkaKeyID: kafkaKeyID, Ledgers: []*LedgerRequest{ledgers[0], {Identity: "peerB", Path: "../b"}}},
+	} {
+		requestBytes, err := json.Marshal(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader(requestBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected the job with %s to be rejected, got status %d", name, response.StatusCode)
+		}
+	}
+
+	response, err := http.Get(ts.URL + "/jobs")
+	var statuses []*JobStatus
+	decode(t, response, err, http.StatusOK, &statuses)
+	if len(statuses) != 0 {
+		t.Fatalf("Expected no job to be queued, got %d", len(statuses))
+	}
+}
+
+// submitArchives uploads the ledgers of the ledger root as archives, whereby the archive of peerA is compressed
+func submitArchives(t *testing.T, ts *httptest.Server, root string, kafkaKeyID string, status int) *JobStatus {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	requestBytes, err := json.Marshal(&JobRequest{
+		Channel:    ledgertest.ChannelName,
+		KafkaKeyID: kafkaKeyID,
+		Ledgers:    []*LedgerRequest{{Identity: "peerA", Archive: "a"}, {Identity: "peerB", Archive: "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.WriteField("job", string(requestBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		archive, err := evidence.ArchiveDir(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "a" {
+			var compressed bytes.Buffer
+			gz := gzip.NewWriter(&compressed)
+			gz.Write(archive)
+			gz.Close()
+			archive = compressed.Bytes()
+		}
+		part, err := writer.CreateFormFile(name, name+".tar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(archive)
+	}
+	writer.Close()
+
+	response, err := http.Post(ts.URL+"/jobs", writer.FormDataContentType(), &body)
+	submitted := &JobStatus{}
+	decode(t, response, err, status, submitted)
+	return submitted
+}
+
+func TestSubmitArchives(t *testing.T) {
+	config := &Config{}
+	ts, kafkaKeyID, cleanup := kafkaService(t, config)
+	defer cleanup()
+
+	submitted := submitArchives(t, ts, config.LedgerRoot, kafkaKeyID, http.StatusAccepted)
+	polled := pollJob(t, ts, submitted.ID)
+	if polled.State != StateSucceeded || polled.Consistent == nil || !*polled.Consistent {
+		t.Fatalf("Expected the job to succeed on the uploaded ledgers, got %s: %s %v", polled.State, polled.Error, polled.Progress)
+	}
+}
+
+func TestSubmitRejectsOversizedArchive(t *testing.T) {
+	// the compressed archive fits into the upload, but not its decompression
+	config := &Config{MaxUploadBytes: 1 << 20, MaxArchiveBytes: 1024}
+	ts, kafkaKeyID, cleanup := kafkaService(t, config)
+	defer cleanup()
+
+	submitArchives(t, ts, config.LedgerRoot, kafkaKeyID, http.StatusBadRequest)
+}
@@ -0,0 +1,404 @@
+package verifier
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	kf "github.com/hyperledger/fabric_judge/protos/kafka"
+)
+
+// StreamMessageType distinguishes the Kafka messages that the orderer consumes from its partition
+type StreamMessageType int
+
+const (
+	RegularMessage StreamMessageType = iota
+	ConfigMessage
+	TTCMessage
+	ConnectMessage
+)
+
+// CutReason states why the simulated orderer cut a block
+type CutReason int
+
+const (
+	CutByTTC CutReason = iota
+	CutByMaxBatchSize
+	CutByPreferredMaxBytes
+	CutByIsolation
+	CutByConfigMessage
+	CutForConfigMessage
+)
+
+func (r CutReason) String() string {
+	switch r {
+	case CutByTTC:
+		return "time-to-cut message"
+	case CutByMaxBatchSize:
+		return "MaxMessageCount reached"
+	case CutByPreferredMaxBytes:
+		return "next message exceeds PreferredMaxBytes"
+	case CutByIsolation:
+		return "message isolated (exceeds PreferredMaxBytes)"
+	case CutByConfigMessage:
+		return "config message isolated"
+	case CutForConfigMessage:
+		return "pending batch cut before config message"
+	}
+	return "unknown"
+}
+
+// StreamMessage is a single Kafka message of the partition, rebuilt from the ledger
+type StreamMessage struct {
+	Offset         int64
+	Timestamp      int64
+	Type           StreamMessageType
+	Envelope       *cb.Envelope
+	TTCBlockNumber uint64
+	// Block is the number of the block in which the message was found
+	Block int
+}
+
+// ExpectedBlock is a block which the simulated orderer has cut
+type ExpectedBlock struct {
+	Number  uint64
+	Offsets []int64
+	Reason  CutReason
+}
+
+// BlockCuttingDiff contains the difference between one expected and one actual block
+type BlockCuttingDiff struct {
+	Number uint64
+	// ExpectedNumber is the number of the block, which the Block-Cutting algorithm cut at the position of the actual block
+	ExpectedNumber uint64
+	Expected       []int64
+	Actual         []int64
+	Reason         string
+}
+
+func (d *BlockCuttingDiff) String() string {
+	if d.Number != d.ExpectedNumber {
+		return fmt.Sprintf("block %d: expected block number %d with offsets %v (%s), actual offsets %v", d.Number, d.ExpectedNumber, d.Expected, d.Reason, d.Actual)
+	}
+	return fmt.Sprintf("block %d: expected offsets %v (%s), actual offsets %v", d.Number, d.Expected, d.Reason, d.Actual)
+}
+
+// BlockCutter re-implements the Ordered() and Cut() semantics of Fabric's blockcutter
+type BlockCutter struct {
+	PreferredMaxBytes int
+	MaxBatchSize      int
+	MessageSizeBytes  SizeAccounting
+
+	pendingBatch          []*StreamMessage
+	pendingBatchSizeBytes int
+}
+
+// NewBlockCutter creates an empty BlockCutter with the given batch size parameters
+func NewBlockCutter(maxBatchSize int, preferredMaxBytes int, sizeAccounting SizeAccounting) *BlockCutter {
+	return &BlockCutter{
+		PreferredMaxBytes: preferredMaxBytes,
+		MaxBatchSize:      maxBatchSize,
+		MessageSizeBytes:  sizeAccounting,
+	}
+}
+
+// Ordered enqueues a regular message and returns the batches which have to be cut as a result
+func (bc *BlockCutter) Ordered(msg *StreamMessage) (messageBatches [][]*StreamMessage, reasons []CutReason, pending bool) {
+	size := bc.MessageSizeBytes(msg.Envelope)
+
+	if size > bc.PreferredMaxBytes {
+		// cut the pending batch, if any, and isolate the message in its own batch
+		if len(bc.pendingBatch) > 0 {
+			messageBatches = append(messageBatches, bc.Cut())
+			reasons = append(reasons, CutByPreferredMaxBytes)
+		}
+		messageBatches = append(messageBatches, []*StreamMessage{msg})
+		reasons = append(reasons, CutByIsolation)
+		return
+	}
+
+	if bc.pendingBatchSizeBytes+size > bc.PreferredMaxBytes {
+		messageBatches = append(messageBatches, bc.Cut())
+		reasons = append(reasons, CutByPreferredMaxBytes)
+	}
+
+	bc.pendingBatch = append(bc.pendingBatch, msg)
+	bc.pendingBatchSizeBytes += size
+	pending = true
+
+	if len(bc.pendingBatch) >= bc.MaxBatchSize {
+		messageBatches = append(messageBatches, bc.Cut())
+		reasons = append(reasons, CutByMaxBatchSize)
+		pending = false
+	}
+
+	return
+}
+
+// Cut returns the pending batch and resets the BlockCutter
+func (bc *BlockCutter) Cut() []*StreamMessage {
+	batch := bc.pendingBatch
+	bc.pendingBatch = nil
+	bc.pendingBatchSizeBytes = 0
+	return batch
+}
+
+// Pending returns the messages which have been ordered but not cut yet
+func (bc *BlockCutter) Pending() []*StreamMessage {
+	return bc.pendingBatch
+}
+
+// RebuildKafkaStream collects all regular, config, TTC and connect messages of the ledger and sorts them by their Kafka offset
+func (v *Verifier) RebuildKafkaStream() ([]*StreamMessage, error) {
+	stream := make([]*StreamMessage, 0)
+
+	for i := 0; i < len(v.Envelopes); i++ {
+		for _, env := range v.Envelopes[i] {
+			if env.KafkaPayload == nil {
+				// envelopes of the genesis block were not ordered by Kafka
+				continue
+			}
+			msgType := RegularMessage
+			if env.KafkaPayload.KafkaRegularMessage != nil && env.KafkaPayload.KafkaRegularMessage.Class == cb.KafkaReg_Payload_CONFIG {
+				msgType = ConfigMessage
+			}
+			stream = append(stream, &StreamMessage{
+				Offset:    env.KafkaPayload.KafkaOffset,
+				Timestamp: env.KafkaPayload.KafkaTimestamp,
+				Type:      msgType,
+				Envelope:  env,
+				Block:     i,
+			})
+		}
+
+		payloads := v.KafkaMetadata[i].ConnectOrTTCPayload
+		if v.KafkaMetadata[i].ReceivedTTCMessage {
+			payloads = append([]*kf.KafkaPayload{v.KafkaMetadata[i].TTCPayload}, payloads...)
+		}
+		for _, payload := range payloads {
+			msg, err := streamMessageFromPayload(payload, i)
+			if err != nil {
+				return nil, err
+			}
+			stream = append(stream, msg)
+		}
+	}
+
+	sort.SliceStable(stream, func(a, b int) bool {
+		return stream[a].Offset < stream[b].Offset
+	})
+
+	return stream, nil
+}
+
+// kafkaChain re-implements the processing of consumed Kafka messages by Fabric's Kafka-based orderer
+type kafkaChain struct {
+	blockCutter        *BlockCutter
+	lastCutBlockNumber uint64
+	expected           []*ExpectedBlock
+	// restarts contains the numbers of the blocks, which do not follow the previous block of the ledger, by their position
+	// among the cut blocks. The ledger of an evidence bundle or a resumed run skips blocks, thus the numbering of the chain
+	// restarts with the number of the block after the gap. All other blocks are numbered by the chain itself
+	restarts map[int]uint64
+
+	// timerStart is the message which started the batch timer, or nil if the timer is not running
+	timerStart *StreamMessage
+}
+
+func newKafkaChain(blockCutter *BlockCutter, lastCutBlockNumber uint64, restarts map[int]uint64) *kafkaChain {
+	return &kafkaChain{
+		blockCutter:        blockCutter,
+		expected:           make([]*ExpectedBlock, 0),
+		lastCutBlockNumber: lastCutBlockNumber,
+		restarts:           restarts,
+	}
+}
+
+// newKafkaChain creates the chain, which cuts the blocks following the genesis block of the ledger.
+// A block, whose PreviousHash does not reference the header of the previous block of the ledger, follows a gap. Tampering
+// with the PreviousHash to hide a misnumbered block is rendered by the verification of the hash chain
+func (v *Verifier) newKafkaChain() *kafkaChain {
+	var genesisNumber uint64
+	if len(v.BlockNumbers) > 0 {
+		genesisNumber = v.BlockNumbers[0]
+	}
+	restarts := make(map[int]uint64)
+	for k := 1; k < len(v.Blocks); k++ {
+		if !bytes.Equal(v.Blocks[k].Header.PreviousHash, BlockHeaderHash(v.Blocks[k-1].Header)) {
+			restarts[k-1] = v.BlockNumbers[k]
+		}
+	}
+	return newKafkaChain(NewBlockCutter(v.MaxBatchSize, v.PreferredMaxBytes, v.MessageSizeBytes), genesisNumber, restarts)
+}
+
+// nextBlockNumber returns the number of the block, which is cut next
+func (c *kafkaChain) nextBlockNumber() uint64 {
+	if number, ok := c.restarts[len(c.expected)]; ok {
+		return number
+	}
+	return c.lastCutBlockNumber + 1
+}
+
+func (c *kafkaChain) cut(batch []*StreamMessage, reason CutReason) {
+	c.lastCutBlockNumber = c.nextBlockNumber()
+	c.expected = append(c.expected, &ExpectedBlock{
+		Number:  c.lastCutBlockNumber,
+		Offsets: offsetsOf(batch),
+		Reason:  reason,
+	})
+}
+
+// process consumes the next message of the stream and returns the number of blocks cut by it
+func (c *kafkaChain) process(msg *StreamMessage) int {
+	numberOfBlocks := len(c.expected)
+
+	switch msg.Type {
+	case RegularMessage:
+		batches, reasons, pending := c.blockCutter.Ordered(msg)
+		for j, batch := range batches {
+			c.cut(batch, reasons[j])
+		}
+		if c.timerStart != nil && !pending {
+			c.timerStart = nil
+		} else if c.timerStart == nil && pending {
+			c.timerStart = msg
+		}
+	case ConfigMessage:
+		if batch := c.blockCutter.Cut(); len(batch) > 0 {
+			c.cut(batch, CutForConfigMessage)
+		}
+		c.cut([]*StreamMessage{msg}, CutByConfigMessage)
+		c.timerStart = nil
+	case TTCMessage:
+		// stale TTC messages and TTC messages without pending requests are ignored
+		if msg.TTCBlockNumber == c.nextBlockNumber() {
+			c.timerStart = nil
+			if batch := c.blockCutter.Cut(); len(batch) > 0 {
+				c.cut(batch, CutByTTC)
+			}
+		}
+	case ConnectMessage:
+		// connect messages are ignored by all orderers
+	}
+
+	return len(c.expected) - numberOfBlocks
+}
+
+// SimulateBlockCutting replays the Kafka stream and returns the blocks the orderer should have cut, together with the batch that is still pending at the end of the stream
+func (v *Verifier) SimulateBlockCutting(stream []*StreamMessage) ([]*ExpectedBlock, []*StreamMessage) {
+	chain := v.newKafkaChain()
+
+	for _, msg := range stream {
+		chain.process(msg)
+	}
+
+	return chain.expected, chain.blockCutter.Pending()
+}
+
+// DiffBlockCutting compares the expected blocks with the actual blocks of the ledger.
+// The returned flag is true, if the last actual block equals the pending batch, such that its cut cannot be verified
+func (v *Verifier) DiffBlockCutting(expected []*ExpectedBlock, pending []*StreamMessage) ([]*BlockCuttingDiff, bool) {
+	diffs := make([]*BlockCuttingDiff, 0)
+	unverifiableLastBlock := false
+
+	for i := 1; i < len(v.Envelopes); i++ {
+		actual := envelopeOffsetsOf(v.Envelopes[i])
+
+		if i-1 < len(expected) {
+			if !EqualOffsets(expected[i-1].Offsets, actual) || expected[i-1].Number != v.BlockNumbers[i] {
+				diffs = append(diffs, &BlockCuttingDiff{
+					Number:         v.BlockNumbers[i],
+					ExpectedNumber: expected[i-1].Number,
+					Expected:       expected[i-1].Offsets,
+					Actual:         actual,
+					Reason:         expected[i-1].Reason.String(),
+				})
+			}
+			continue
+		}
+
+		// the remaining messages were not cut by the simulation, because the message causing the cut is not part of the ledger
+		pendingOffsets := offsetsOf(pending)
+		if i == len(v.Envelopes)-1 && EqualOffsets(pendingOffsets, actual) {
+			unverifiableLastBlock = true
+			continue
+		}
+		diffs = append(diffs, &BlockCuttingDiff{
+			Number:         v.BlockNumbers[i],
+			ExpectedNumber: v.BlockNumbers[i],
+			Expected:       pendingOffsets,
+			Actual:         actual,
+			Reason:         "pending, not cut yet",
+		})
+		pending = nil
+	}
+
+	for k := len(v.Envelopes) - 1; k < len(expected); k++ {
+		diffs = append(diffs, &BlockCuttingDiff{
+			Number:         expected[k].Number,
+			ExpectedNumber: expected[k].Number,
+			Expected:       expected[k].Offsets,
+			Actual:         nil,
+			Reason:         expected[k].Reason.String(),
+		})
+	}
+
+	return diffs, unverifiableLastBlock
+}
+
+func streamMessageFromPayload(payload *kf.KafkaPayload, block int) (*StreamMessage, error) {
+	consumerMessage, err := DecodeConsumerMessage(payload.ConsumerMessageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode consumer message in block %d: %s", block, err)
+	}
+
+	msg := &StreamMessage{
+		Offset:    consumerMessage.Offset,
+		Timestamp: consumerMessage.Timestamp,
+		Block:     block,
+	}
+
+	switch consumerMessage.Message.Type.(type) {
+	case *kf.KafkaMessage_TimeToCut:
+		msg.Type = TTCMessage
+		msg.TTCBlockNumber = consumerMessage.Message.GetTimeToCut().GetBlockNumber()
+	case *kf.KafkaMessage_Connect:
+		msg.Type = ConnectMessage
+	default:
+		return nil, fmt.Errorf("Metadata of block %d contains a Kafka message which is neither a TTC nor a connect message", block)
+	}
+
+	return msg, nil
+}
+
+func offsetsOf(batch []*StreamMessage) []int64 {
+	offsets := make([]int64, len(batch))
+	for i, msg := range batch {
+		offsets[i] = msg.Offset
+	}
+	return offsets
+}
+
+func envelopeOffsetsOf(envelopes []*cb.Envelope) []int64 {
+	offsets := make([]int64, 0, len(envelopes))
+	for _, env := range envelopes {
+		if env.KafkaPayload != nil {
+			offsets = append(offsets, env.KafkaPayload.KafkaOffset)
+		}
+	}
+	return offsets
+}
+
+// EqualOffsets returns true, if both lists contain the same Kafka offsets in the same order
+func EqualOffsets(a []int64, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
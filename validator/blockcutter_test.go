@@ -0,0 +1,171 @@
+package verifier_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func regular(offset int64, size int) *validator.StreamMessage {
+	return &validator.StreamMessage{Offset: offset, Type: validator.RegularMessage, Envelope: &cb.Envelope{Payload: make([]byte, size)}}
+}
+
+func config(offset int64) *validator.StreamMessage {
+	return &validator.StreamMessage{Offset: offset, Type: validator.ConfigMessage, Envelope: &cb.Envelope{Payload: make([]byte, 10)}}
+}
+
+func ttc(offset int64, blockNumber uint64) *validator.StreamMessage {
+	return &validator.StreamMessage{Offset: offset, Type: validator.TTCMessage, TTCBlockNumber: blockNumber}
+}
+
+func TestSimulateBlockCutting(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		stream   []*validator.StreamMessage
+		expected []*validator.ExpectedBlock
+		pending  []int64
+	}{
+		{
+			name:     "MaxMessageCount",
+			stream:   []*validator.StreamMessage{regular(0, 10), regular(1, 10), regular(2, 10)},
+			expected: []*validator.ExpectedBlock{{Number: 1, Offsets: []int64{0, 1}, Reason: validator.CutByMaxBatchSize}},
+			pending:  []int64{2},
+		},
+		{
+			name:     "PreferredMaxBytes",
+			stream:   []*validator.StreamMessage{regular(0, 60), regular(1, 60)},
+			expected: []*validator.ExpectedBlock{{Number: 1, Offsets: []int64{0}, Reason: validator.CutByPreferredMaxBytes}},
+			pending:  []int64{1},
+		},
+		{
+			name:   "message exceeding PreferredMaxBytes is isolated",
+			stream: []*validator.StreamMessage{regular(0, 10), regular(1, 150), regular(2, 10)},
+			expected: []*validator.ExpectedBlock{
+				{Number: 1, Offsets: []int64{0}, Reason: validator.CutByPreferredMaxBytes},
+				{Number: 2, Offsets: []int64{1}, Reason: validator.CutByIsolation},
+			},
+			pending: []int64{2},
+		},
+		{
+			name:   "config message is isolated",
+			stream: []*validator.StreamMessage{regular(0, 10), config(1), regular(2, 10)},
+			expected: []*validator.ExpectedBlock{
+				{Number: 1, Offsets: []int64{0}, Reason: validator.CutForConfigMessage},
+				{Number: 2, Offsets: []int64{1}, Reason: validator.CutByConfigMessage},
+			},
+			pending: []int64{2},
+		},
+		{
+			name:   "TTC message",
+			stream: []*validator.StreamMessage{regular(0, 10), ttc(1, 1), regular(2, 10), ttc(3, 2)},
+			expected: []*validator.ExpectedBlock{
+				{Number: 1, Offsets: []int64{0}, Reason: validator.CutByTTC},
+				{Number: 2, Offsets: []int64{2}, Reason: validator.CutByTTC},
+			},
+		},
+		{
+			name:     "stale TTC message is ignored",
+			stream:   []*validator.StreamMessage{regular(0, 10), ttc(1, 1), regular(2, 10), ttc(3, 1)},
+			expected: []*validator.ExpectedBlock{{Number: 1, Offsets: []int64{0}, Reason: validator.CutByTTC}},
+			pending:  []int64{2},
+		},
+		{
+			name:     "TTC message without pending messages is ignored",
+			stream:   []*validator.StreamMessage{ttc(0, 1), regular(1, 10), regular(2, 10)},
+			expected: []*validator.ExpectedBlock{{Number: 1, Offsets: []int64{1, 2}, Reason: validator.CutByMaxBatchSize}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v := &validator.Verifier{
+				Blocks:            []*cb.Block{{Header: &cb.BlockHeader{Number: 0}}},
+				BlockNumbers:      []uint64{0},
+				MaxBatchSize:      2,
+				PreferredMaxBytes: 100,
+				MessageSizeBytes:  validator.FabricMessageSizeBytes,
+			}
+			expected, pending := v.SimulateBlockCutting(test.stream)
+			if !reflect.DeepEqual(expected, test.expected) {
+				t.Errorf("Expected blocks %v, got %v", blocksString(test.expected), blocksString(expected))
+			}
+			pendingOffsets := make([]int64, 0)
+			for _, msg := range pending {
+				pendingOffsets = append(pendingOffsets, msg.Offset)
+			}
+			if !validator.EqualOffsets(pendingOffsets, test.pending) {
+				t.Errorf("Expected pending offsets %v, got %v", test.pending, pendingOffsets)
+			}
+		})
+	}
+}
+
+func blocksString(blocks []*validator.ExpectedBlock) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		parts = append(parts, fmt.Sprintf("block %d %v (%s)", block.Number, block.Offsets, block.Reason))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// verifyBlockCutting replays the block cutting of the ledger with the parameters of the fixture
+func verifyBlockCutting(t *testing.T, f *ledgertest.KafkaFixture, blocks []*cb.Block) string {
+	v, err := validator.NewVerifier(blocks, f.KafkaPublicKey, "peerA", ledgertest.MaxBatchSize, 50000, 100000, validator.KafkaProofMessageSizeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verdict := v.VerifyBlockCuttingOfOrderer()
+	if len(verdict) == 0 {
+		return ""
+	}
+	return verdict[0].Message()
+}
+
+func TestDiffBlockCutting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockcutter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3, TTC: 2}}, {{Offset: 4, Config: true}}, {{Offset: 5}, {Offset: 6}}})
+	if message := verifyBlockCutting(t, f, blocks); message != "" {
+		t.Fatalf("Expected the correctly cut ledger to pass, got: %s", message)
+	}
+
+	// the window of an evidence bundle skips blocks after the genesis block
+	window := []*cb.Block{blocks[0], blocks[3], blocks[4]}
+	if message := verifyBlockCutting(t, f, window); message != "" {
+		t.Fatalf("Expected the window of the ledger to pass, got: %s", message)
+	}
+
+	early := f.Chain([][]ledgertest.Message{{{Offset: 0}}, {{Offset: 1}, {Offset: 2}}})
+	if message := verifyBlockCutting(t, f, early); message != "Orderer cut block 1 too early" {
+		t.Fatalf("Expected the block cut without cause to be rendered, got: %q", message)
+	}
+}
+
+func TestDiffBlockCuttingRendersMisnumberedBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockcutter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	// the orderer skips block number 2, while the ledger stays linked by the hashes of the headers
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+	misnumbered := f.Block(blocks[1], []ledgertest.Message{{Offset: 2}, {Offset: 3}}, 0, 0)
+	misnumbered.Header.Number = 3
+	blocks = append(blocks, misnumbered, f.Block(misnumbered, []ledgertest.Message{{Offset: 4}, {Offset: 5}}, 0, 0))
+
+	if message := verifyBlockCutting(t, f, blocks); message != "Orderer numbered block 3, which the Block-Cutting algorithm cut as block 2" {
+		t.Fatalf("Expected the misnumbered block to be rendered, got: %q", message)
+	}
+}
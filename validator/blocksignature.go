@@ -0,0 +1,174 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/msp"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// BlockHeaderBytes returns the ASN.1 encoding of the block header, which is hashed and signed by the orderers
+func BlockHeaderBytes(header *cb.BlockHeader) []byte {
+	result, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		// the header only contains byte slices and a big integer, which can always be encoded
+		panic(err)
+	}
+	return result
+}
+
+// BlockHeaderHash returns the hash of the block header, which the next block references as its PreviousHash
+func BlockHeaderHash(header *cb.BlockHeader) []byte {
+	hash := sha256.Sum256(BlockHeaderBytes(header))
+	return hash[:]
+}
+
+// BlockDataHash returns the hash over all envelopes of the block, which is stored in the DataHash of the header
+func BlockDataHash(data *cb.BlockData) []byte {
+	h := sha256.New()
+	for _, d := range data.Data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// VerifyHashChain checks that the blocks are numbered consecutively, that the DataHash of every block matches its envelopes
+// and that every block references the header of its predecessor. On failure, the index of the invalid block is returned as well
+func VerifyHashChain(blocks []*cb.Block) (int, error) {
+	for i, block := range blocks {
+		if block.Header.Number != uint64(i) {
+			return i, fmt.Errorf("Peer committed block %d at height %d", block.Header.Number, i)
+		}
+		err := verifyHashLink(blocks, i, false)
+		if err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+// VerifyHashLinks performs the checks of VerifyHashChain on blocks, which may skip block numbers, e.g. the blocks of an evidence bundle.
+// The PreviousHash is only checked, if the predecessor of a block is part of the given blocks.
+// The envelopes of redacted blocks were removed from an evidence bundle, thus their DataHash cannot be recomputed and
+// they are only authenticated by the signatures of their headers
+func VerifyHashLinks(blocks []*cb.Block, redacted map[uint64]bool) (int, error) {
+	for i, block := range blocks {
+		if i > 0 && block.Header.Number <= blocks[i-1].Header.Number {
+			return i, fmt.Errorf("Peer committed block %d after block %d", block.Header.Number, blocks[i-1].Header.Number)
+		}
+		err := verifyHashLink(blocks, i, redacted[block.Header.Number])
+		if err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+func verifyHashLink(blocks []*cb.Block, i int, redacted bool) error {
+	block := blocks[i]
+	if !redacted && !bytes.Equal(block.Header.DataHash, BlockDataHash(block.Data)) {
+		return fmt.Errorf("Peer committed block %d, whose DataHash does not match its envelopes", block.Header.Number)
+	}
+	if i > 0 && blocks[i-1].Header.Number+1 == block.Header.Number && !bytes.Equal(block.Header.PreviousHash, BlockHeaderHash(blocks[i-1].Header)) {
+		return fmt.Errorf("Peer committed block %d, whose PreviousHash does not match the header of block %d", block.Header.Number, blocks[i-1].Header.Number)
+	}
+	return nil
+}
+
+// HashChainEvidence returns the numbers of the invalid block and of its predecessor, which it has to reference
+func HashChainEvidence(blocks []*cb.Block, invalid int) []uint64 {
+	if invalid == 0 {
+		return []uint64{blocks[0].Header.Number}
+	}
+	return []uint64{blocks[invalid-1].Header.Number, blocks[invalid].Header.Number}
+}
+
+// GetOrdererSignersOfBlock verifies all signatures in the SIGNATURES metadata of the block and returns the orderer identities, which validly signed it.
+// Signatures of identities, which do not belong to an orderer organization of the channel, are not counted
+func GetOrdererSignersOfBlock(block *cb.Block, mspManager *msp.Manager) ([]*msp.Identity, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_SIGNATURES) {
+		return nil, fmt.Errorf("block %d does not contain SIGNATURES metadata", block.Header.Number)
+	}
+
+	metadata := &cb.Metadata{}
+	err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES], metadata)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal SIGNATURES metadata of block %d: %s", block.Header.Number, err)
+	}
+
+	headerBytes := BlockHeaderBytes(block.Header)
+	signers := make([]*msp.Identity, 0)
+	seen := make(map[string]bool)
+
+	at := signingTimeOfBlock(block)
+
+	for _, metadataSignature := range metadata.Signatures {
+		signatureHeader := &cb.SignatureHeader{}
+		err = proto.Unmarshal(metadataSignature.SignatureHeader, signatureHeader)
+		if err != nil {
+			continue
+		}
+
+		identity, err := mspManager.DeserializeIdentity(signatureHeader.Creator, at)
+		if err != nil || !mspManager.IsOrdererMSP(identity.MSPID) {
+			continue
+		}
+
+		signedBytes := make([]byte, 0, len(metadata.Value)+len(metadataSignature.SignatureHeader)+len(headerBytes))
+		signedBytes = append(signedBytes, metadata.Value...)
+		signedBytes = append(signedBytes, metadataSignature.SignatureHeader...)
+		signedBytes = append(signedBytes, headerBytes...)
+
+		if identity.Verify(signedBytes, metadataSignature.Signature) != nil {
+			continue
+		}
+		// every orderer is only counted once, even if it signed the block multiple times
+		if seen[string(identity.Certificate.Raw)] {
+			continue
+		}
+		seen[string(identity.Certificate.Raw)] = true
+		signers = append(signers, identity)
+	}
+
+	return signers, nil
+}
+
+// signingTimeOfBlock approximates the time the block was signed by the latest channel header timestamp of its envelopes,
+// such that orderer certificates, which expired in the meantime, are still accepted
+func signingTimeOfBlock(block *cb.Block) time.Time {
+	var at time.Time
+	for _, data := range block.Data.Data {
+		env := &cb.Envelope{}
+		if proto.Unmarshal(data, env) != nil {
+			continue
+		}
+		channelHeader, err := GetChannelHeaderFromEnvelope(env)
+		if err != nil || channelHeader.Timestamp == nil {
+			continue
+		}
+		timestamp := time.Unix(channelHeader.Timestamp.Seconds, int64(channelHeader.Timestamp.Nanos))
+		if timestamp.After(at) {
+			at = timestamp
+		}
+	}
+	if at.IsZero() {
+		return time.Now()
+	}
+	return at
+}
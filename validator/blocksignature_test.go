@@ -0,0 +1,47 @@
+package verifier_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func TestVerifyHashChain(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"}, []string{"tx2"})
+	if _, err := validator.VerifyHashChain(blocks); err != nil {
+		t.Fatalf("Expected the hash chain to be valid, got: %s", err)
+	}
+
+	// block 2 does not reference the header of the block before it
+	unlinked := append(blocks[:2:2], f.Block(f.Genesis(), f.Consenters, "tx2"))
+	unlinked[2].Header.Number = 2
+	invalid, err := validator.VerifyHashChain(unlinked)
+	if err == nil || invalid != 2 || err.Error() != "Peer committed block 2, whose PreviousHash does not match the header of block 1" {
+		t.Fatalf("Expected the broken link of block 2 to be detected, got %d: %v", invalid, err)
+	}
+}
+
+func TestVerifyHashLinks(t *testing.T) {
+	f := ledgertest.NewBFTFixture(t, 4)
+	blocks := f.Chain([]string{"tx1"}, []string{"tx2"}, []string{"tx3"})
+
+	// the window of an evidence bundle skips block 1, and the envelopes of block 3 are redacted
+	redacted := &cb.Block{Header: blocks[3].Header, Data: &cb.BlockData{}, Metadata: blocks[3].Metadata}
+	window := []*cb.Block{blocks[0], blocks[2], redacted}
+	if _, err := validator.VerifyHashLinks(window, map[uint64]bool{3: true}); err != nil {
+		t.Fatalf("Expected the window to be valid, got: %s", err)
+	}
+
+	invalid, err := validator.VerifyHashLinks(window, nil)
+	if err == nil || invalid != 2 || err.Error() != "Peer committed block 3, whose DataHash does not match its envelopes" {
+		t.Fatalf("Expected the removed envelopes of block 3 to be detected, got %d: %v", invalid, err)
+	}
+
+	invalid, err = validator.VerifyHashLinks([]*cb.Block{blocks[0], blocks[2], blocks[1]}, nil)
+	if err == nil || invalid != 2 || err.Error() != "Peer committed block 1 after block 2" {
+		t.Fatalf("Expected the blocks out of order to be detected, got %d: %v", invalid, err)
+	}
+}
@@ -0,0 +1,107 @@
+package verifier
+
+import (
+	"fmt"
+
+	kf "github.com/hyperledger/fabric_judge/protos/kafka"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyKafkaMetadataBookkeeping checks the offsets, which the orderer records in the KafkaMetadata of every block:
+// LastOffsetPersisted must equal the highest Kafka offset consumed into the block and must never go backwards.
+// LastOriginalOffsetProcessed and LastResubmittedConfigOffset must never go backwards either and must be consistent with the re-submitted messages of the ledger
+func (v *Verifier) VerifyKafkaMetadataBookkeeping() []*verdicts.Verdict {
+	originalOffsets := v.originalOffsetsOfLedger()
+
+	var lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset int64
+
+	// starting at i = 1, since the genesis block does not contain Kafka messages
+	for i := 1; i < len(v.KafkaMetadata); i++ {
+		metadata := v.KafkaMetadata[i]
+		blockNumber := v.BlockNumbers[i]
+
+		highestOffset, err := v.highestConsumedOffset(i)
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+		}
+		if metadata.LastOffsetPersisted != highestOffset {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer recorded LastOffsetPersisted %d for block %d, but the highest consumed offset is %d", metadata.LastOffsetPersisted, blockNumber, highestOffset), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		if i > 1 && metadata.LastOffsetPersisted <= lastOffsetPersisted {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastOffsetPersisted of block %d (%d) does not advance beyond the one of the previous block (%d)", blockNumber, metadata.LastOffsetPersisted, lastOffsetPersisted), v.Identity, 1).WithEvidence(v.Identity, blockNumber-1, blockNumber)}
+		}
+
+		if metadata.LastOriginalOffsetProcessed < lastOriginalOffsetProcessed {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastOriginalOffsetProcessed of block %d (%d) goes backwards (previously %d)", blockNumber, metadata.LastOriginalOffsetProcessed, lastOriginalOffsetProcessed), v.Identity, 1).WithEvidence(v.Identity, blockNumber-1, blockNumber)}
+		}
+		// the re-submitted messages of skipped blocks are not part of a ledger with gaps, e.g. of a resumed run
+		if metadata.LastOriginalOffsetProcessed != lastOriginalOffsetProcessed && blockNumber == v.BlockNumbers[i-1]+1 && !originalOffsets[metadata.LastOriginalOffsetProcessed] {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastOriginalOffsetProcessed of block %d (%d) is not the original offset of any re-submitted message", blockNumber, metadata.LastOriginalOffsetProcessed), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		for _, env := range v.Envelopes[i] {
+			if env.KafkaPayload == nil || env.KafkaPayload.KafkaRegularMessage == nil {
+				continue
+			}
+			if env.KafkaPayload.KafkaRegularMessage.OriginalOffset > metadata.LastOriginalOffsetProcessed {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d contains the re-submitted message with original offset %d, but LastOriginalOffsetProcessed is only %d", blockNumber, env.KafkaPayload.KafkaRegularMessage.OriginalOffset, metadata.LastOriginalOffsetProcessed), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+		}
+		if metadata.LastOriginalOffsetProcessed > metadata.LastOffsetPersisted {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastOriginalOffsetProcessed of block %d (%d) exceeds LastOffsetPersisted (%d)", blockNumber, metadata.LastOriginalOffsetProcessed, metadata.LastOffsetPersisted), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+
+		if metadata.LastResubmittedConfigOffset < lastResubmittedConfigOffset {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastResubmittedConfigOffset of block %d (%d) goes backwards (previously %d)", blockNumber, metadata.LastResubmittedConfigOffset, lastResubmittedConfigOffset), v.Identity, 1).WithEvidence(v.Identity, blockNumber-1, blockNumber)}
+		}
+		if metadata.LastResubmittedConfigOffset > metadata.LastOffsetPersisted {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LastResubmittedConfigOffset of block %d (%d) exceeds LastOffsetPersisted (%d)", blockNumber, metadata.LastResubmittedConfigOffset, metadata.LastOffsetPersisted), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+
+		lastOffsetPersisted = metadata.LastOffsetPersisted
+		lastOriginalOffsetProcessed = metadata.LastOriginalOffsetProcessed
+		lastResubmittedConfigOffset = metadata.LastResubmittedConfigOffset
+	}
+
+	return nil
+}
+
+// highestConsumedOffset returns the highest Kafka offset of all envelopes, TTC and connect messages of block i
+func (v *Verifier) highestConsumedOffset(i int) (int64, error) {
+	highestOffset := int64(-1)
+
+	for _, env := range v.Envelopes[i] {
+		if env.KafkaPayload != nil && env.KafkaPayload.KafkaOffset > highestOffset {
+			highestOffset = env.KafkaPayload.KafkaOffset
+		}
+	}
+
+	metadata := v.KafkaMetadata[i]
+	payloads := metadata.ConnectOrTTCPayload
+	if metadata.TTCPayload != nil {
+		payloads = append([]*kf.KafkaPayload{metadata.TTCPayload}, payloads...)
+	}
+	for _, payload := range payloads {
+		consumerMessage, err := DecodeConsumerMessage(payload.ConsumerMessageBytes)
+		if err != nil {
+			return 0, fmt.Errorf("Unable to decode consumer message in block %d: %s", v.BlockNumbers[i], err)
+		}
+		if consumerMessage.Offset > highestOffset {
+			highestOffset = consumerMessage.Offset
+		}
+	}
+
+	return highestOffset, nil
+}
+
+// originalOffsetsOfLedger returns the set of original offsets of all re-submitted messages of the ledger
+func (v *Verifier) originalOffsetsOfLedger() map[int64]bool {
+	originalOffsets := make(map[int64]bool)
+	for _, blockEnv := range v.Envelopes {
+		for _, env := range blockEnv {
+			if env.KafkaPayload != nil && env.KafkaPayload.KafkaRegularMessage != nil && env.KafkaPayload.KafkaRegularMessage.OriginalOffset != 0 {
+				originalOffsets[env.KafkaPayload.KafkaRegularMessage.OriginalOffset] = true
+			}
+		}
+	}
+	return originalOffsets
+}
@@ -0,0 +1,38 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestVerifyKafkaMetadataBookkeeping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bookkeeping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3, TTC: 2}}})
+
+	v := newKafkaVerifier(t, f, blocks)
+	if verdict := v.VerifyKafkaMetadataBookkeeping(); len(verdict) != 0 {
+		t.Fatalf("Expected the recorded offsets to pass, got: %s", verdict[0].Message())
+	}
+
+	// the TTC message is the highest offset consumed into block 2
+	v.KafkaMetadata[2].LastOffsetPersisted = 2
+	verdict := v.VerifyKafkaMetadataBookkeeping()
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer recorded LastOffsetPersisted 2 for block 2, but the highest consumed offset is 3" {
+		t.Fatalf("Expected the wrong LastOffsetPersisted to be detected, got %v", verdict)
+	}
+
+	v = newKafkaVerifier(t, f, blocks)
+	v.KafkaMetadata[1].LastResubmittedConfigOffset = 1
+	verdict = v.VerifyKafkaMetadataBookkeeping()
+	if len(verdict) != 1 || verdict[0].Message() != "LastResubmittedConfigOffset of block 2 (0) goes backwards (previously 1)" {
+		t.Fatalf("Expected LastResubmittedConfigOffset going backwards to be detected, got %v", verdict)
+	}
+}
@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// DefaultTimestampWindow is the maximum difference between the timestamp of a channel header and the Kafka timestamp of its message
+const DefaultTimestampWindow = 15 * time.Minute
+
+// DefaultAllowedHeaderTypes contains the header types, which an orderer may order on an application channel.
+// ORDERER_TRANSACTION is not among them, since channel creation requests are only ordered on the orderer system channel
+var DefaultAllowedHeaderTypes = map[cb.HeaderType]bool{
+	cb.HeaderType_MESSAGE:              true,
+	cb.HeaderType_CONFIG:               true,
+	cb.HeaderType_ENDORSER_TRANSACTION: true,
+}
+
+// VerifyChannelHeaders decodes the channel header of every ordered envelope and checks that it is bound for the judged channel,
+// is of an allowed type, carries a tx_id derived from its nonce and creator and was created close to its Kafka timestamp
+func (v *Verifier) VerifyChannelHeaders(channelName string, allowedHeaderTypes map[cb.HeaderType]bool, timestampWindow time.Duration) []*verdicts.Verdict {
+	// starting at i = 1, since the envelopes of the genesis block were not ordered
+	for i := 1; i < len(v.Envelopes); i++ {
+		for tIdx, env := range v.Envelopes[i] {
+			err := verifyChannelHeader(env, channelName, allowedHeaderTypes, timestampWindow)
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer ordered envelope %d of block %d with an invalid channel header: %s", tIdx, v.BlockNumbers[i], err), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+			}
+		}
+	}
+	return nil
+}
+
+func verifyChannelHeader(env *cb.Envelope, channelName string, allowedHeaderTypes map[cb.HeaderType]bool, timestampWindow time.Duration) error {
+	channelHeader, err := GetChannelHeaderFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+
+	if channelHeader.ChannelId != channelName {
+		return fmt.Errorf("envelope is bound for channel %s instead of %s", channelHeader.ChannelId, channelName)
+	}
+
+	headerType := cb.HeaderType(channelHeader.Type)
+	if !allowedHeaderTypes[headerType] {
+		return fmt.Errorf("header type %s is not allowed", headerType)
+	}
+
+	// Fabric only requires a tx_id for endorser transactions, config transactions created by the orderer do not carry one
+	if channelHeader.TxId != "" || headerType == cb.HeaderType_ENDORSER_TRANSACTION {
+		if channelHeader.TxId == "" {
+			return fmt.Errorf("tx_id is missing")
+		}
+		signatureHeader, err := GetSignatureHeaderFromEnvelope(env)
+		if err != nil {
+			return err
+		}
+		expectedTxID := ComputeTxID(signatureHeader.Nonce, signatureHeader.Creator)
+		if channelHeader.TxId != expectedTxID {
+			return fmt.Errorf("tx_id %s does not match the nonce and creator (expected %s)", channelHeader.TxId, expectedTxID)
+		}
+	}
+
+	if env.KafkaPayload != nil && channelHeader.Timestamp != nil {
+		headerTime := time.Unix(channelHeader.Timestamp.Seconds, int64(channelHeader.Timestamp.Nanos))
+		kafkaTime := time.Unix(0, env.KafkaPayload.KafkaTimestamp*int64(KafkaTimestampUnit))
+		difference := headerTime.Sub(kafkaTime)
+		if difference > timestampWindow || difference < -timestampWindow {
+			return fmt.Errorf("timestamp %s differs by %s from the Kafka timestamp %s", headerTime.UTC(), difference, kafkaTime.UTC())
+		}
+	} else if channelHeader.Timestamp == nil && headerType == cb.HeaderType_ENDORSER_TRANSACTION {
+		return fmt.Errorf("timestamp is missing")
+	}
+
+	return nil
+}
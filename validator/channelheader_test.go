@@ -0,0 +1,35 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func TestVerifyChannelHeaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "channelheader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	v := newKafkaVerifier(t, f, f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}}))
+
+	if verdict := v.VerifyChannelHeaders(ledgertest.ChannelName, validator.DefaultAllowedHeaderTypes, validator.DefaultTimestampWindow); len(verdict) != 0 {
+		t.Fatalf("Expected the channel headers to pass, got: %s", verdict[0].Message())
+	}
+
+	verdict := v.VerifyChannelHeaders("other", validator.DefaultAllowedHeaderTypes, validator.DefaultTimestampWindow)
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer ordered envelope 0 of block 1 with an invalid channel header: envelope is bound for channel ch instead of other" {
+		t.Fatalf("Expected the envelope of another channel to be detected, got %v", verdict)
+	}
+
+	verdict = v.VerifyChannelHeaders(ledgertest.ChannelName, map[cb.HeaderType]bool{cb.HeaderType_CONFIG: true}, validator.DefaultTimestampWindow)
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer ordered envelope 0 of block 1 with an invalid channel header: header type MESSAGE is not allowed" {
+		t.Fatalf("Expected the envelope of a disallowed header type to be detected, got %v", verdict)
+	}
+}
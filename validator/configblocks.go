@@ -0,0 +1,75 @@
+package verifier
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyConfigBlocks checks that config messages were isolated in their own blocks and that the LAST_CONFIG metadata of every block points at the most recent config block.
+// Instead of trusting the IsConfigMessage flag, the ChannelHeader type and the Kafka class of every envelope are inspected
+func (v *Verifier) VerifyConfigBlocks() []*verdicts.Verdict {
+	// the genesis block is the first config block
+	var lastConfigBlock uint64
+
+	for i := 0; i < len(v.Envelopes); i++ {
+		blockNumber := v.BlockNumbers[i]
+
+		if i > 0 {
+			isConfigBlock, verdict := v.verifyConfigIsolation(i)
+			if verdict != nil {
+				return verdict
+			}
+			if isConfigBlock {
+				lastConfigBlock = blockNumber
+			}
+		}
+
+		lastConfigIndex, err := GetLastConfigIndexFromBlock(v.Blocks[i])
+		if err != nil {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LAST_CONFIG metadata of block %d is invalid: %s", blockNumber, err), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		if lastConfigIndex != lastConfigBlock {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("LAST_CONFIG metadata of block %d points at block %d, but the most recent config block is %d", blockNumber, lastConfigIndex, lastConfigBlock), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+	}
+
+	return nil
+}
+
+// verifyConfigIsolation checks the config rules for block i and returns whether it is a config block
+func (v *Verifier) verifyConfigIsolation(i int) (bool, []*verdicts.Verdict) {
+	blockNumber := v.BlockNumbers[i]
+	isConfigMessage := v.KafkaMetadata[i].IsConfigMessage
+
+	if isConfigMessage && len(v.Envelopes[i]) != 1 {
+		return false, []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Config block %d contains %d envelopes instead of exactly one", blockNumber, len(v.Envelopes[i])), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+	}
+
+	for _, env := range v.Envelopes[i] {
+		channelHeader, err := GetChannelHeaderFromEnvelope(env)
+		if err != nil {
+			return false, []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to decode the channel header of an envelope in block %d: %s", blockNumber, err), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		isConfigEnvelope := isConfigHeaderType(channelHeader.Type)
+		isConfigClass := env.KafkaPayload != nil && env.KafkaPayload.KafkaRegularMessage != nil && env.KafkaPayload.KafkaRegularMessage.Class == cb.KafkaReg_Payload_CONFIG
+
+		if isConfigMessage {
+			if !isConfigEnvelope {
+				return false, []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d is flagged as config block, but its envelope is of type %s", blockNumber, cb.HeaderType(channelHeader.Type)), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			if !isConfigClass {
+				return false, []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d is flagged as config block, but its envelope was not ordered as Kafka CONFIG message", blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+		} else if isConfigEnvelope || isConfigClass {
+			return false, []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Block %d is not flagged as config block, but contains a config envelope", blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+	}
+
+	return isConfigMessage, nil
+}
+
+func isConfigHeaderType(headerType int32) bool {
+	return headerType == int32(cb.HeaderType_CONFIG) || headerType == int32(cb.HeaderType_ORDERER_TRANSACTION)
+}
@@ -0,0 +1,36 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestVerifyConfigBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configblocks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}})
+
+	if verdict := newKafkaVerifier(t, f, blocks).VerifyConfigBlocks(); len(verdict) != 0 {
+		t.Fatalf("Expected the isolated config message to pass, got: %s", verdict[0].Message())
+	}
+
+	// block 3 still points at the genesis block
+	outdated := append(blocks[:3:3], f.Block(blocks[2], []ledgertest.Message{{Offset: 3}, {Offset: 4}}, 1, 0))
+	verdict := newKafkaVerifier(t, f, outdated).VerifyConfigBlocks()
+	if len(verdict) != 1 || verdict[0].Message() != "LAST_CONFIG metadata of block 3 points at block 0, but the most recent config block is 2" {
+		t.Fatalf("Expected the outdated LAST_CONFIG metadata to be detected, got %v", verdict)
+	}
+
+	unisolated := append(blocks[:2:2], f.Block(blocks[1], []ledgertest.Message{{Offset: 2, Config: true}, {Offset: 3}}, 0, 2))
+	verdict = newKafkaVerifier(t, f, unisolated).VerifyConfigBlocks()
+	if len(verdict) != 1 || verdict[0].Message() != "Config block 2 contains 2 envelopes instead of exactly one" {
+		t.Fatalf("Expected the config message sharing its block to be detected, got %v", verdict)
+	}
+}
@@ -0,0 +1,59 @@
+package verifier
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyConfigSequences tracks the config sequence of the channel through the ledger and checks that every committed message
+// was validated against the current config sequence. Messages with a stale config sequence must have been re-validated and re-submitted,
+// and re-submitted copies must have been deduplicated using their original offset
+func (v *Verifier) VerifyConfigSequences() []*verdicts.Verdict {
+	committedOffsets := make(map[int64]bool)
+	for _, blockEnv := range v.Envelopes {
+		for _, env := range blockEnv {
+			if env.KafkaPayload != nil {
+				committedOffsets[env.KafkaPayload.KafkaOffset] = true
+			}
+		}
+	}
+
+	// the genesis block contains the config with sequence 0
+	var configSeq uint64
+	var lastOriginalOffsetProcessed int64
+
+	for i := 1; i < len(v.Envelopes); i++ {
+		for _, env := range v.Envelopes[i] {
+			if env.KafkaPayload == nil || env.KafkaPayload.KafkaRegularMessage == nil {
+				continue
+			}
+			offset := env.KafkaPayload.KafkaOffset
+			regularMessage := env.KafkaPayload.KafkaRegularMessage
+
+			if regularMessage.OriginalOffset != 0 {
+				if regularMessage.OriginalOffset <= lastOriginalOffsetProcessed {
+					return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer committed the re-submitted message at offset %d in block %d, although original offset %d was already processed", offset, v.BlockNumbers[i], regularMessage.OriginalOffset), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+				}
+				if committedOffsets[regularMessage.OriginalOffset] {
+					return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer committed both the original message at offset %d and its re-submitted copy at offset %d in block %d", regularMessage.OriginalOffset, offset, v.BlockNumbers[i]), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+				}
+				lastOriginalOffsetProcessed = regularMessage.OriginalOffset
+			}
+
+			if regularMessage.ConfigSeq < configSeq {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer committed the message at offset %d in block %d with stale config sequence %d (current config sequence is %d) instead of re-validating and re-submitting it", offset, v.BlockNumbers[i], regularMessage.ConfigSeq, configSeq), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+			}
+			if regularMessage.ConfigSeq > configSeq {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Message at offset %d in block %d claims config sequence %d, but the current config sequence is %d", offset, v.BlockNumbers[i], regularMessage.ConfigSeq, configSeq), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+			}
+
+			if regularMessage.Class == cb.KafkaReg_Payload_CONFIG {
+				configSeq++
+			}
+		}
+	}
+
+	return nil
+}
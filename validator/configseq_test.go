@@ -0,0 +1,36 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestVerifyConfigSequences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configseq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2, Config: true}}, {{Offset: 3}, {Offset: 4}}})
+
+	if verdict := newKafkaVerifier(t, f, blocks).VerifyConfigSequences(); len(verdict) != 0 {
+		t.Fatalf("Expected the messages validated against the current config to pass, got: %s", verdict[0].Message())
+	}
+
+	// the messages of block 3 were validated before the config message, but not re-validated afterwards
+	stale := append(blocks[:3:3], f.Block(blocks[2], []ledgertest.Message{{Offset: 3}, {Offset: 4}}, 0, 2))
+	verdict := newKafkaVerifier(t, f, stale).VerifyConfigSequences()
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer committed the message at offset 3 in block 3 with stale config sequence 0 (current config sequence is 1) instead of re-validating and re-submitting it" {
+		t.Fatalf("Expected the message with stale config sequence to be detected, got %v", verdict)
+	}
+
+	future := append(blocks[:1:1], f.Block(blocks[0], []ledgertest.Message{{Offset: 0}, {Offset: 1}}, 1, 0))
+	verdict = newKafkaVerifier(t, f, future).VerifyConfigSequences()
+	if len(verdict) != 1 || verdict[0].Message() != "Message at offset 0 in block 1 claims config sequence 1, but the current config sequence is 0" {
+		t.Fatalf("Expected the message with a future config sequence to be detected, got %v", verdict)
+	}
+}
@@ -0,0 +1,74 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/channelconfig"
+	"github.com/hyperledger/fabric_judge/msp"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyCreatorSignatures verifies the client signature of every ordered envelope with an offline MSP, which is built from the channel config in effect.
+// A forged or mis-signed envelope is blamed on the Kafka Cluster, if it signed the envelope into its stream, and on the orderer otherwise
+func (v *Verifier) VerifyCreatorSignatures() []*verdicts.Verdict {
+	var mspManager *msp.Manager
+	var err error
+
+	for i := 0; i < len(v.Envelopes); i++ {
+		if i == 0 {
+			mspManager, err = msp.NewManagerFromConfigBlock(v.Blocks[0])
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to build the MSPs from the genesis block: %s", err), v.Identity, 1)}
+			}
+			continue
+		}
+
+		for tIdx, env := range v.Envelopes[i] {
+			err = verifyCreatorSignature(env, mspManager)
+			if err == nil {
+				continue
+			}
+			msg := fmt.Sprintf("Envelope %d of block %d is not properly signed by its creator: %s", tIdx, v.BlockNumbers[i], err)
+			if v.IsSignedByKafka(env) {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(msg, "Kafka Cluster", 0).WithEvidence(v.Identity, v.BlockNumbers[i])}
+			}
+			return []*verdicts.Verdict{verdicts.CreateVerdict(msg, v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i])}
+		}
+
+		// the following envelopes are validated against the updated config. The block itself is inspected, since the
+		// metadata written by the orderer must not decide, which config the envelopes are validated against
+		if channelconfig.IsConfigBlock(v.Blocks[i]) {
+			mspManager, err = msp.NewManagerFromConfigBlock(v.Blocks[i])
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Unable to build the MSPs from config block %d: %s", v.BlockNumbers[i], err), v.Identity, 1)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func verifyCreatorSignature(env *cb.Envelope, mspManager *msp.Manager) error {
+	signatureHeader, err := GetSignatureHeaderFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+
+	// certificates are validated at the time the envelope was created, since they may have expired in the meantime
+	at := time.Now()
+	channelHeader, err := GetChannelHeaderFromEnvelope(env)
+	if err == nil && channelHeader.Timestamp != nil {
+		at = time.Unix(channelHeader.Timestamp.Seconds, int64(channelHeader.Timestamp.Nanos))
+	} else if env.KafkaPayload != nil {
+		at = time.Unix(0, env.KafkaPayload.KafkaTimestamp*int64(KafkaTimestampUnit))
+	}
+
+	identity, err := mspManager.DeserializeIdentity(signatureHeader.Creator, at)
+	if err != nil {
+		return err
+	}
+
+	return identity.Verify(env.Payload, env.Signature)
+}
@@ -0,0 +1,115 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	kf "github.com/hyperledger/fabric_judge/protos/kafka"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// SignedOffset is a Kafka offset together with the hash of its leaf and the root, under which Kafka signed it
+type SignedOffset struct {
+	Offset   int64
+	LeafHash []byte
+	RootHash []byte
+	Block    uint64
+}
+
+// IndexSignedOffsets collects the signed (offset, leaf hash, root) tuple of every Kafka message of the ledger
+func (v *Verifier) IndexSignedOffsets() ([]*SignedOffset, error) {
+	index := make([]*SignedOffset, 0)
+
+	add := func(offset int64, encProof []byte, leafHash []byte, block uint64) {
+		index = append(index, &SignedOffset{
+			Offset:   offset,
+			LeafHash: leafHash,
+			RootHash: GetProofFromBytes(encProof).RootHash,
+			Block:    block,
+		})
+	}
+
+	for i, blockEnv := range v.Envelopes {
+		for _, env := range blockEnv {
+			if env.KafkaPayload != nil {
+				add(env.KafkaPayload.KafkaOffset, env.KafkaPayload.KafkaMerkleProofHeader, v.KafkaLeafHash(env), v.BlockNumbers[i])
+			}
+		}
+
+		metadata := v.KafkaMetadata[i]
+		payloads := metadata.ConnectOrTTCPayload
+		if metadata.TTCPayload != nil {
+			payloads = append([]*kf.KafkaPayload{metadata.TTCPayload}, payloads...)
+		}
+		for _, payload := range payloads {
+			consumerMessage, err := DecodeConsumerMessage(payload.ConsumerMessageBytes)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to decode consumer message in block %d: %s", v.BlockNumbers[i], err)
+			}
+			add(consumerMessage.Offset, payload.KafkaMerkleProofHeader, leafHashOf(payload.ConsumerMessageBytes), v.BlockNumbers[i])
+		}
+	}
+
+	return index, nil
+}
+
+// VerifyOffsetEquivocation analyses a single ledger for evidence of Kafka equivocation:
+// The same offset must not be signed with different leaf contents and the offsets of consecutive signed batches must not overlap
+func (v *Verifier) VerifyOffsetEquivocation() []*verdicts.Verdict {
+	index, err := v.IndexSignedOffsets()
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	byOffset := make(map[int64]*SignedOffset)
+	for _, signedOffset := range index {
+		other, ok := byOffset[signedOffset.Offset]
+		if ok && !bytes.Equal(other.LeafHash, signedOffset.LeafHash) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Kafka signed offset %d with different contents under root %s (block %d) and root %s (block %d) of %s", signedOffset.Offset, hex.EncodeToString(other.RootHash), other.Block, hex.EncodeToString(signedOffset.RootHash), signedOffset.Block, v.Identity), "Kafka Cluster", 0).WithEvidence(v.Identity, other.Block, signedOffset.Block)}
+		}
+		byOffset[signedOffset.Offset] = signedOffset
+	}
+
+	// each signed batch covers the range between its lowest and highest offset
+	type offsetRange struct {
+		rootHash           []byte
+		min, max           int64
+		minBlock, maxBlock uint64
+	}
+	ranges := make(map[string]*offsetRange)
+	for _, signedOffset := range index {
+		key := hex.EncodeToString(signedOffset.RootHash)
+		r, ok := ranges[key]
+		if !ok {
+			ranges[key] = &offsetRange{rootHash: signedOffset.RootHash, min: signedOffset.Offset, max: signedOffset.Offset, minBlock: signedOffset.Block, maxBlock: signedOffset.Block}
+			continue
+		}
+		if signedOffset.Offset < r.min {
+			r.min = signedOffset.Offset
+			r.minBlock = signedOffset.Block
+		}
+		if signedOffset.Offset > r.max {
+			r.max = signedOffset.Offset
+			r.maxBlock = signedOffset.Block
+		}
+	}
+
+	sortedRanges := make([]*offsetRange, 0, len(ranges))
+	for _, r := range ranges {
+		sortedRanges = append(sortedRanges, r)
+	}
+	sort.Slice(sortedRanges, func(a, b int) bool {
+		return sortedRanges[a].min < sortedRanges[b].min
+	})
+
+	for k := 1; k < len(sortedRanges); k++ {
+		previous, current := sortedRanges[k-1], sortedRanges[k]
+		if current.min <= previous.max {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Kafka signed overlapping batches in the ledger of %s: root %s covers offsets %d to %d, root %s covers offsets %d to %d", v.Identity, hex.EncodeToString(previous.rootHash), previous.min, previous.max, hex.EncodeToString(current.rootHash), current.min, current.max), "Kafka Cluster", 0).WithEvidence(v.Identity, previous.minBlock, previous.maxBlock, current.minBlock, current.maxBlock)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+func TestVerifyOffsetEquivocation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "equivocation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	if verdict := newKafkaVerifier(t, f, blocks).VerifyOffsetEquivocation(); len(verdict) != 0 {
+		t.Fatalf("Expected the consecutive batches to pass, got: %s", verdict[0].Message())
+	}
+
+	// Kafka signs offset 1 a second time with another message
+	equivocated := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 1, Data: "other"}, {Offset: 2}}})
+	verdict := newKafkaVerifier(t, f, equivocated).VerifyOffsetEquivocation()
+	if len(verdict) != 1 || verdict[0].Identity() != "Kafka Cluster" || !strings.HasPrefix(verdict[0].Message(), "Kafka signed offset 1 with different contents under root") {
+		t.Fatalf("Expected Kafka to be blamed for signing offset 1 twice, got %v", verdict)
+	}
+}
+
+func TestVerifyOffsetEquivocationOfOverlappingBatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "equivocation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	// the same message of offset 1 is signed under the roots of both batches
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+	env := new(cb.Envelope)
+	if err := proto.Unmarshal(blocks[1].Data.Data[1], env); err != nil {
+		t.Fatal(err)
+	}
+	blocks = append(blocks, f.Block(blocks[1], []ledgertest.Message{{Offset: 1, Replay: env}, {Offset: 2}}, 0, 0))
+	verdict := newKafkaVerifier(t, f, blocks).VerifyOffsetEquivocation()
+	if len(verdict) != 1 || verdict[0].Identity() != "Kafka Cluster" || !strings.HasPrefix(verdict[0].Message(), "Kafka signed overlapping batches in the ledger of peerA") {
+		t.Fatalf("Expected Kafka to be blamed for the overlapping batches, got %v", verdict)
+	}
+}
@@ -0,0 +1,257 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// merkleLeaf is a leaf of a Merkle tree signed by Kafka, together with its proof
+type merkleLeaf struct {
+	proof    Proof
+	encProof []byte
+	leafHash []byte
+	origin   string
+
+	// ledger and block locate the leaf, such that it can be exported as evidence
+	ledger string
+	block  uint64
+}
+
+// merkleBatch contains all leaves which were proven against the same signed root
+type merkleBatch struct {
+	rootHash []byte
+	leaves   []*merkleLeaf
+
+	// nodes maps the level and index of a node to its hash, as claimed by the proofs
+	nodes map[[2]int][]byte
+}
+
+// VerifyMerkleBatches groups all Merkle proofs of the given ledgers by their signed root and checks that they are consistent with each other:
+// Leaf indices must be unique and within LeafSize, all proofs must agree on LeafSize and sibling hashes must agree along shared paths.
+// If all leaves of a tree are present, the tree is rebuilt. Inconsistencies are evidence against the Kafka Cluster, since it signed the root
+func VerifyMerkleBatches(verifiers ...*Verifier) []*verdicts.Verdict {
+	batches := make(map[string]*merkleBatch)
+	for _, v := range verifiers {
+		v.addMerkleLeaves(batches)
+	}
+
+	rootHashes := make([]string, 0, len(batches))
+	for rootHash := range batches {
+		rootHashes = append(rootHashes, rootHash)
+	}
+	sort.Strings(rootHashes)
+
+	var result []*verdicts.Verdict
+	for _, rootHash := range rootHashes {
+		err := batches[rootHash].verify()
+		if err != nil {
+			verdict := verdicts.CreateVerdict(fmt.Sprintf("Kafka signed inconsistent Merkle proofs under root %s: %s", rootHash, err), "Kafka Cluster", 0)
+			for _, leaf := range batches[rootHash].leaves {
+				verdict.WithEvidence(leaf.ledger, leaf.block)
+			}
+			result = append(result, verdict)
+		}
+	}
+	return result
+}
+
+// addMerkleLeaves adds the Merkle leaves of all Kafka messages of the ledger to their batches, including the open batches of the checkpoint it resumes from
+func (v *Verifier) addMerkleLeaves(batches map[string]*merkleBatch) {
+	for _, leaf := range v.ResumedMerkleLeaves {
+		addMerkleLeaf(batches, leaf.Proof, leaf.LeafHash, leaf.Origin, v.Identity, leaf.Block)
+	}
+
+	for i, blockEnv := range v.Envelopes {
+		for tIdx, env := range blockEnv {
+			if env.KafkaPayload == nil {
+				continue
+			}
+			origin := fmt.Sprintf("envelope %d of block %d of %s", tIdx, v.BlockNumbers[i], v.Identity)
+			addMerkleLeaf(batches, env.KafkaPayload.KafkaMerkleProofHeader, v.KafkaLeafHash(env), origin, v.Identity, v.BlockNumbers[i])
+		}
+
+		metadata := v.KafkaMetadata[i]
+		if metadata.TTCPayload != nil {
+			origin := fmt.Sprintf("TTC message of block %d of %s", v.BlockNumbers[i], v.Identity)
+			addMerkleLeaf(batches, metadata.TTCPayload.KafkaMerkleProofHeader, leafHashOf(metadata.TTCPayload.ConsumerMessageBytes), origin, v.Identity, v.BlockNumbers[i])
+		}
+		for j, payload := range metadata.ConnectOrTTCPayload {
+			origin := fmt.Sprintf("connect or TTC message %d of block %d of %s", j, v.BlockNumbers[i], v.Identity)
+			addMerkleLeaf(batches, payload.KafkaMerkleProofHeader, leafHashOf(payload.ConsumerMessageBytes), origin, v.Identity, v.BlockNumbers[i])
+		}
+	}
+}
+
+// OpenMerkleLeaves returns the Merkle leaves of the ledger, including the resumed ones, whose batch does not contain all leaves of its tree.
+// The leaves of a complete tree are bound to its signed root, thus only the open batches can be continued by later blocks
+func (v *Verifier) OpenMerkleLeaves() []*MerkleLeafRecord {
+	batches := make(map[string]*merkleBatch)
+	v.addMerkleLeaves(batches)
+
+	rootHashes := make([]string, 0, len(batches))
+	for rootHash := range batches {
+		rootHashes = append(rootHashes, rootHash)
+	}
+	sort.Strings(rootHashes)
+
+	records := make([]*MerkleLeafRecord, 0)
+	for _, rootHash := range rootHashes {
+		batch := batches[rootHash]
+		indices := make(map[int]bool)
+		for _, leaf := range batch.leaves {
+			indices[leaf.proof.LeafIndex] = true
+		}
+		if len(indices) == batch.leaves[0].proof.LeafSize {
+			continue
+		}
+		for _, leaf := range batch.leaves {
+			records = append(records, &MerkleLeafRecord{Proof: leaf.encProof, LeafHash: leaf.leafHash, Origin: leaf.origin, Block: leaf.block})
+		}
+	}
+	return records
+}
+
+func leafHashOf(data []byte) []byte {
+	leafHash := sha256.Sum256(data)
+	return leafHash[:]
+}
+
+func addMerkleLeaf(batches map[string]*merkleBatch, encProof []byte, leafHash []byte, origin string, ledger string, block uint64) {
+	proof := GetProofFromBytes(encProof)
+	rootHash := hex.EncodeToString(proof.RootHash)
+
+	batch, ok := batches[rootHash]
+	if !ok {
+		batch = &merkleBatch{
+			rootHash: proof.RootHash,
+			nodes:    make(map[[2]int][]byte),
+		}
+		batches[rootHash] = batch
+	}
+	batch.leaves = append(batch.leaves, &merkleLeaf{proof: proof, encProof: encProof, leafHash: leafHash, origin: origin, ledger: ledger, block: block})
+}
+
+func (batch *merkleBatch) verify() error {
+	leafSize := batch.leaves[0].proof.LeafSize
+	depth := merkleTreeDepth(leafSize)
+	leafHashes := make(map[int][]byte)
+
+	for _, leaf := range batch.leaves {
+		if leaf.proof.LeafSize != leafSize {
+			return fmt.Errorf("%s claims LeafSize %d, whereas %s claims LeafSize %d", leaf.origin, leaf.proof.LeafSize, batch.leaves[0].origin, leafSize)
+		}
+		if leaf.proof.LeafIndex < 0 || leaf.proof.LeafIndex >= leafSize {
+			return fmt.Errorf("LeafIndex %d of %s is not within LeafSize %d", leaf.proof.LeafIndex, leaf.origin, leafSize)
+		}
+		if len(leaf.proof.ProofSet) != depth {
+			return fmt.Errorf("proof of %s contains %d hashes, but a tree with %d leaves has depth %d", leaf.origin, len(leaf.proof.ProofSet), leafSize, depth)
+		}
+
+		if other, ok := leafHashes[leaf.proof.LeafIndex]; ok && !bytes.Equal(other, leaf.leafHash) {
+			return fmt.Errorf("LeafIndex %d is claimed by different messages, one of them is %s", leaf.proof.LeafIndex, leaf.origin)
+		}
+		leafHashes[leaf.proof.LeafIndex] = leaf.leafHash
+
+		err := batch.claimPath(leaf, leaf.leafHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(leafHashes) == leafSize {
+		rootHash := rebuildMerkleRoot(leafHashes, leafSize)
+		if !bytes.Equal(rootHash, batch.rootHash) {
+			return fmt.Errorf("the rebuilt tree of all %d leaves does not match the signed root", leafSize)
+		}
+	}
+
+	return nil
+}
+
+// claimPath records the hashes of all nodes along the path of the leaf and of their siblings, and checks them against the claims of the other proofs
+func (batch *merkleBatch) claimPath(leaf *merkleLeaf, leafHash []byte) error {
+	index := leaf.proof.LeafIndex
+	size := leaf.proof.LeafSize
+	node := leafHash
+
+	for level := 0; level < len(leaf.proof.ProofSet); level++ {
+		sibling := leaf.proof.ProofSet[level]
+
+		err := batch.claim(level, index, node, leaf.origin)
+		if err != nil {
+			return err
+		}
+
+		h := sha256.New()
+		if index == size-1 && index%2 == 0 {
+			// the last node of a level with odd size is paired with itself
+			err = batch.claim(level, index, sibling, leaf.origin)
+			h.Write(sibling)
+			h.Write(node)
+		} else if index%2 == 1 {
+			err = batch.claim(level, index-1, sibling, leaf.origin)
+			h.Write(sibling)
+			h.Write(node)
+		} else {
+			err = batch.claim(level, index+1, sibling, leaf.origin)
+			h.Write(node)
+			h.Write(sibling)
+		}
+		if err != nil {
+			return err
+		}
+
+		node = h.Sum(nil)
+		size = size/2 + size%2
+		index = index / 2
+	}
+
+	return nil
+}
+
+func (batch *merkleBatch) claim(level int, index int, hash []byte, origin string) error {
+	key := [2]int{level, index}
+	if other, ok := batch.nodes[key]; ok && !bytes.Equal(other, hash) {
+		return fmt.Errorf("the proof of %s disagrees with another proof on the hash of node %d at level %d", origin, index, level)
+	}
+	batch.nodes[key] = hash
+	return nil
+}
+
+func merkleTreeDepth(leafSize int) int {
+	depth := 0
+	for leafSize > 1 {
+		leafSize = leafSize/2 + leafSize%2
+		depth++
+	}
+	return depth
+}
+
+func rebuildMerkleRoot(leafHashes map[int][]byte, leafSize int) []byte {
+	level := make([][]byte, leafSize)
+	for i := 0; i < leafSize; i++ {
+		level[i] = leafHashes[i]
+	}
+
+	for len(level) > 1 {
+		next := make([][]byte, 0, len(level)/2+1)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			h := sha256.New()
+			h.Write(level[i])
+			h.Write(right)
+			next = append(next, h.Sum(nil))
+		}
+		level = next
+	}
+
+	return level[0]
+}
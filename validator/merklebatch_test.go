@@ -0,0 +1,108 @@
+package verifier_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func newKafkaVerifier(t *testing.T, f *ledgertest.KafkaFixture, blocks []*cb.Block) *validator.Verifier {
+	v, err := validator.NewVerifier(blocks, f.KafkaPublicKey, "peerA", ledgertest.MaxBatchSize, 50000, 100000, validator.KafkaProofMessageSizeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestOpenMerkleLeaves(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merklebatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+
+	if open := newKafkaVerifier(t, f, blocks).OpenMerkleLeaves(); len(open) != 0 {
+		t.Fatalf("Expected the complete batch not to be carried over, got %d leaves", len(open))
+	}
+
+	// the second message of the batch is cut into the next block
+	partial := &cb.Block{Header: blocks[1].Header, Data: &cb.BlockData{Data: blocks[1].Data.Data[:1]}, Metadata: blocks[1].Metadata}
+	open := newKafkaVerifier(t, f, []*cb.Block{blocks[0], partial}).OpenMerkleLeaves()
+	if len(open) != 1 || open[0].Block != 1 {
+		t.Fatalf("Expected the single leaf of the open batch, got %d leaves", len(open))
+	}
+}
+
+func TestVerifyMerkleBatchesWithResumedLeaves(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merklebatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+	first := &cb.Block{Header: blocks[1].Header, Data: &cb.BlockData{Data: blocks[1].Data.Data[:1]}, Metadata: blocks[1].Metadata}
+	second := &cb.Block{Header: blocks[1].Header, Data: &cb.BlockData{Data: blocks[1].Data.Data[1:]}, Metadata: blocks[1].Metadata}
+	open := newKafkaVerifier(t, f, []*cb.Block{blocks[0], first}).OpenMerkleLeaves()
+
+	// the batch is completed after the checkpoint
+	resumed := newKafkaVerifier(t, f, []*cb.Block{blocks[0], second})
+	resumed.ResumedMerkleLeaves = open
+	if verdict := validator.VerifyMerkleBatches(resumed); len(verdict) != 0 {
+		t.Fatalf("Expected the batch to be consistent across the checkpoint, got: %s", verdict[0].Message())
+	}
+	if carried := resumed.OpenMerkleLeaves(); len(carried) != 0 {
+		t.Fatalf("Expected the completed batch not to be carried over, got %d leaves", len(carried))
+	}
+
+	// a different message claims the leaf index of the message before the checkpoint
+	conflicting := *open[0]
+	conflicting.LeafHash = make([]byte, len(open[0].LeafHash))
+	resumed.ResumedMerkleLeaves = []*validator.MerkleLeafRecord{&conflicting}
+	if verdict := validator.VerifyMerkleBatches(resumed); len(verdict) == 0 {
+		t.Fatal("Expected the leaf claimed by different messages across the checkpoint to be detected")
+	}
+}
+
+func TestVerifyMerkleBatchesAcrossLedgers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merklebatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}, {{Offset: 2}, {Offset: 3}}})
+	peerB, err := validator.NewVerifier(blocks, f.KafkaPublicKey, "peerB", ledgertest.MaxBatchSize, 50000, 100000, validator.KafkaProofMessageSizeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict := validator.VerifyMerkleBatches(newKafkaVerifier(t, f, blocks), peerB); len(verdict) != 0 {
+		t.Fatalf("Expected the proofs of both ledgers to be consistent, got: %s", verdict[0].Message())
+	}
+
+	// the leaf of the first message claims a larger tree than the leaf of the second message under the same root
+	first := &cb.Block{Header: blocks[1].Header, Data: &cb.BlockData{Data: blocks[1].Data.Data[:1]}, Metadata: blocks[1].Metadata}
+	open := newKafkaVerifier(t, f, []*cb.Block{blocks[0], first}).OpenMerkleLeaves()
+	tampered := *open[0]
+	tampered.Proof = append([]byte{}, open[0].Proof...)
+	binary.BigEndian.PutUint32(tampered.Proof[12:16], 3)
+	second := &cb.Block{Header: blocks[1].Header, Data: &cb.BlockData{Data: blocks[1].Data.Data[1:]}, Metadata: blocks[1].Metadata}
+	peerB, err = validator.NewVerifier([]*cb.Block{blocks[0], second}, f.KafkaPublicKey, "peerB", ledgertest.MaxBatchSize, 50000, 100000, validator.KafkaProofMessageSizeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerB.ResumedMerkleLeaves = []*validator.MerkleLeafRecord{&tampered}
+
+	verdict := validator.VerifyMerkleBatches(newKafkaVerifier(t, f, blocks), peerB)
+	if len(verdict) != 1 || verdict[0].Identity() != "Kafka Cluster" || !strings.Contains(verdict[0].Message(), "claims LeafSize 3, whereas") {
+		t.Fatalf("Expected Kafka to be blamed for the inconsistent LeafSize, got %v", verdict)
+	}
+}
@@ -75,6 +75,30 @@ func (proof Proof) VerifyProof(data []byte) bool {
 	return reflect.DeepEqual(data, proof.RootHash)
 }
 
+// VerifyProofOfLeafHash verifies that the leaf with the given hash is part of the kafka message stream, without knowing the message itself
+func (proof Proof) VerifyProofOfLeafHash(leafHash []byte) bool {
+	index := proof.LeafIndex
+	leafSize := proof.LeafSize
+	node := leafHash
+
+	h := sha256.New()
+	for i := 0; i < len(proof.ProofSet); i++ {
+		h.Reset()
+		if index == leafSize-1 || index%2 == 1 {
+			h.Write(proof.ProofSet[i])
+			h.Write(node)
+		} else {
+			h.Write(node)
+			h.Write(proof.ProofSet[i])
+		}
+		node = h.Sum(nil)
+		leafSize = leafSize/2 + leafSize%2
+		index = index / 2
+	}
+
+	return reflect.DeepEqual(node, proof.RootHash)
+}
+
 //VerifiySignature verifies that Kafka actually signed the massage
 func (proof Proof) VerifySignature(sigBytes []byte) error {
 	pk_file, _ := os.Open("/etc/KafkaKeyPair/public.key")
@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// SizeAccounting computes the size of an envelope, which the blockcutter compares against PreferredMaxBytes
+type SizeAccounting func(env *cb.Envelope) int
+
+// DefaultOrdererVersion is the orderer which adds Kafka merkle proofs and signatures to the envelopes
+const DefaultOrdererVersion = "1.4-kafka-proofs"
+
+var sizeAccountingOfVersion = map[string]SizeAccounting{
+	"1.4":                 FabricMessageSizeBytes,
+	DefaultOrdererVersion: KafkaProofMessageSizeBytes,
+}
+
+// SizeAccountingOfVersion returns the byte accounting used by the blockcutter of the given orderer version
+func SizeAccountingOfVersion(version string) (SizeAccounting, error) {
+	sizeAccounting, ok := sizeAccountingOfVersion[version]
+	if !ok {
+		return nil, fmt.Errorf("Unknown orderer version %s", version)
+	}
+	return sizeAccounting, nil
+}
+
+// FabricMessageSizeBytes is the byte accounting of the unmodified Fabric blockcutter
+func FabricMessageSizeBytes(env *cb.Envelope) int {
+	return len(env.Payload) + len(env.Signature)
+}
+
+// KafkaProofMessageSizeBytes is the byte accounting of the orderer, which also accounts for the Kafka merkle proof and signature
+func KafkaProofMessageSizeBytes(env *cb.Envelope) int {
+	if env.KafkaPayload == nil {
+		return FabricMessageSizeBytes(env)
+	}
+	return len(env.Payload) + len(env.Signature) + len(env.KafkaPayload.KafkaMerkleProofHeader) + len(env.KafkaPayload.KafkaSignatureHeader) + 1
+}
+
+// EnvelopeWireSize returns the size of the envelope as it was broadcast to the orderer (i.e. without the KafkaPayload).
+// This is the size, which the orderer compares against AbsoluteMaxBytes
+func EnvelopeWireSize(env *cb.Envelope) int {
+	return proto.Size(&cb.Envelope{
+		Payload:   env.Payload,
+		Signature: env.Signature,
+	})
+}
+
+// VerifyMessageSizes checks that no ordered envelope exceeds AbsoluteMaxBytes and that envelopes exceeding PreferredMaxBytes were isolated
+func (v *Verifier) VerifyMessageSizes() []*verdicts.Verdict {
+	var result []*verdicts.Verdict
+
+	// starting at i = 1, since the envelopes of the genesis block were not ordered
+	for i := 1; i < len(v.Envelopes); i++ {
+		for _, env := range v.Envelopes[i] {
+			if env.KafkaPayload == nil {
+				continue
+			}
+
+			if v.AbsoluteMaxBytes > 0 {
+				wireSize := EnvelopeWireSize(env)
+				if wireSize > v.AbsoluteMaxBytes {
+					result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Orderer accepted envelope at offset %d in block %d of %d bytes, which exceeds AbsoluteMaxBytes (%d)", env.KafkaPayload.KafkaOffset, v.BlockNumbers[i], wireSize, v.AbsoluteMaxBytes), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i]))
+				}
+			}
+
+			size := v.MessageSizeBytes(env)
+			if size > v.PreferredMaxBytes && len(v.Envelopes[i]) > 1 {
+				result = append(result, verdicts.CreateVerdict(fmt.Sprintf("Orderer did not isolate envelope at offset %d in block %d of %d bytes, which exceeds PreferredMaxBytes (%d)", env.KafkaPayload.KafkaOffset, v.BlockNumbers[i], size, v.PreferredMaxBytes), v.Identity, 1).WithEvidence(v.Identity, v.BlockNumbers[i]))
+			}
+		}
+	}
+
+	return result
+}
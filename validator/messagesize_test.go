@@ -0,0 +1,65 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+	validator "github.com/hyperledger/fabric_judge/validator"
+)
+
+func TestSizeAccountingOfVersion(t *testing.T) {
+	env := &cb.Envelope{Payload: make([]byte, 10), Signature: make([]byte, 5), KafkaPayload: &cb.KafkaPayload{KafkaMerkleProofHeader: make([]byte, 20), KafkaSignatureHeader: make([]byte, 64)}}
+	for version, expected := range map[string]int{"1.4": 15, validator.DefaultOrdererVersion: 100} {
+		sizeAccounting, err := validator.SizeAccountingOfVersion(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := sizeAccounting(env); size != expected {
+			t.Errorf("Expected orderer version %s to account %d bytes, got %d", version, expected, size)
+		}
+	}
+
+	// envelopes without Kafka proof, e.g. the ones of the genesis block, are accounted like Fabric does
+	if size := validator.KafkaProofMessageSizeBytes(&cb.Envelope{Payload: make([]byte, 10), Signature: make([]byte, 5)}); size != 15 {
+		t.Errorf("Expected the envelope without Kafka proof to account 15 bytes, got %d", size)
+	}
+	if _, err := validator.SizeAccountingOfVersion("0.1"); err == nil {
+		t.Error("Expected the unknown orderer version to be rejected")
+	}
+}
+
+func TestVerifyMessageSizes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messagesize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+	blocks := f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1}}})
+
+	verify := func(preferredMaxBytes int, absoluteMaxBytes int) string {
+		v, err := validator.NewVerifier(blocks, f.KafkaPublicKey, "peerA", ledgertest.MaxBatchSize, preferredMaxBytes, absoluteMaxBytes, validator.KafkaProofMessageSizeBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verdict := v.VerifyMessageSizes()
+		if len(verdict) == 0 {
+			return ""
+		}
+		return verdict[0].Message()
+	}
+
+	if message := verify(50000, 100000); message != "" {
+		t.Fatalf("Expected the envelopes within the limits to pass, got: %s", message)
+	}
+	if message := verify(50000, 100); !strings.HasPrefix(message, "Orderer accepted envelope at offset 0 in block 1") || !strings.HasSuffix(message, "which exceeds AbsoluteMaxBytes (100)") {
+		t.Fatalf("Expected the envelope exceeding AbsoluteMaxBytes to be detected, got: %q", message)
+	}
+	if message := verify(100, 100000); !strings.HasPrefix(message, "Orderer did not isolate envelope at offset 0 in block 1") {
+		t.Fatalf("Expected the envelope exceeding PreferredMaxBytes to be isolated, got: %q", message)
+	}
+}
@@ -0,0 +1,65 @@
+package verifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	cb "github.com/hyperledger/fabric_judge/protos/common"
+)
+
+// EnvelopeRecord is an envelope of a block, which was verified by a previous run. It is kept in the checkpoint,
+// such that envelopes replayed after the checkpoint are still detected
+type EnvelopeRecord struct {
+	Offset int64  `json:"offset"`
+	Block  uint64 `json:"block"`
+	Hash   string `json:"hash"`
+	TxID   string `json:"tx_id,omitempty"`
+}
+
+// MerkleLeafRecord is a Merkle leaf of a block, which was verified by a previous run, together with its encoded proof.
+// It is kept in the checkpoint, if later blocks may contain further leaves signed under the same root
+type MerkleLeafRecord struct {
+	Proof    []byte `json:"proof"`
+	LeafHash []byte `json:"leaf_hash"`
+	Origin   string `json:"origin"`
+	Block    uint64 `json:"block"`
+}
+
+// EnvelopeHash returns the hash, by which replayed envelopes are identified
+func EnvelopeHash(env *cb.Envelope) []byte {
+	h := sha256.New()
+	h.Write(env.Payload)
+	h.Write(env.Signature)
+	return h.Sum(nil)
+}
+
+// EnvelopeRecords returns the records of all envelopes of the ledger, including the resumed ones, sorted by their Kafka offset
+func (v *Verifier) EnvelopeRecords() []*EnvelopeRecord {
+	byOffset := make(map[int64]*EnvelopeRecord)
+	for _, record := range v.ResumedEnvelopes {
+		byOffset[record.Offset] = record
+	}
+	for i, blockEnv := range v.Envelopes {
+		for _, env := range blockEnv {
+			if env.KafkaPayload == nil {
+				continue
+			}
+			record := &EnvelopeRecord{Offset: env.KafkaPayload.KafkaOffset, Block: v.BlockNumbers[i], Hash: hex.EncodeToString(EnvelopeHash(env))}
+			channelHeader, err := GetChannelHeaderFromEnvelope(env)
+			if err == nil {
+				record.TxID = channelHeader.TxId
+			}
+			byOffset[record.Offset] = record
+		}
+	}
+
+	records := make([]*EnvelopeRecord, 0, len(byOffset))
+	for _, record := range byOffset {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(a, b int) bool {
+		return records[a].Offset < records[b].Offset
+	})
+	return records
+}
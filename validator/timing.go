@@ -0,0 +1,55 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// KafkaTimestampUnit is the resolution of the timestamps which Kafka assigns to the messages
+const KafkaTimestampUnit = time.Millisecond
+
+// VerifyBatchTimeouts uses the Kafka timestamps to check that every TTC message was posted roughly BatchTimeout after the batch timer was started.
+// Withheld TTC messages delay blocks, whereas premature TTC messages fragment them. Since any orderer of the channel may post a TTC message
+// and Kafka does not record its producer, the verdicts are rendered against the ordering service instead of the orderer of the peer
+func (v *Verifier) VerifyBatchTimeouts(batchTimeout time.Duration, tolerance time.Duration) []*verdicts.Verdict {
+	stream, err := v.RebuildKafkaStream()
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
+
+	var result []*verdicts.Verdict
+	chain := v.newKafkaChain()
+	// withheldTimer is the timer start, for which a withheld TTC message was already reported
+	var withheldTimer *StreamMessage
+
+	for _, msg := range stream {
+		timerStart := chain.timerStart
+		blockNumber := chain.nextBlockNumber()
+		cutBlocks := chain.process(msg)
+
+		if timerStart == nil {
+			continue
+		}
+
+		delay := time.Duration(msg.Timestamp-timerStart.Timestamp) * KafkaTimestampUnit
+
+		if msg.Type == TTCMessage && cutBlocks > 0 {
+			if delay < batchTimeout-tolerance {
+				result = append(result, verdicts.CreateTimingVerdict(fmt.Sprintf("Ordering service posted a premature TTC message at offset %d for block %d", msg.Offset, blockNumber), delay, batchTimeout).WithEvidence(v.Identity, v.BlockNumbers[timerStart.Block], v.BlockNumbers[msg.Block]))
+			} else if delay > batchTimeout+tolerance && withheldTimer != timerStart {
+				result = append(result, verdicts.CreateTimingVerdict(fmt.Sprintf("Ordering service withheld the TTC message at offset %d for block %d", msg.Offset, blockNumber), delay, batchTimeout).WithEvidence(v.Identity, v.BlockNumbers[timerStart.Block], v.BlockNumbers[msg.Block]))
+			}
+			continue
+		}
+
+		// the batch timer is still running although it should have expired, thus the TTC message for the pending batch was withheld
+		if delay > batchTimeout+tolerance && withheldTimer != timerStart {
+			withheldTimer = timerStart
+			result = append(result, verdicts.CreateTimingVerdict(fmt.Sprintf("Ordering service withheld the TTC message for block %d, message at offset %d was consumed before the batch timer started at offset %d expired", blockNumber, msg.Offset, timerStart.Offset), delay, batchTimeout).WithEvidence(v.Identity, v.BlockNumbers[timerStart.Block], v.BlockNumbers[msg.Block]))
+		}
+	}
+
+	return result
+}
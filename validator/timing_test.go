@@ -0,0 +1,44 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+func TestVerifyBatchTimeouts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	// the fixture derives the Kafka timestamps from the offsets, thus the TTC message is posted 4ms after the batch timer started
+	v := newKafkaVerifier(t, f, f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 4, TTC: 1}}}))
+
+	if verdict := v.VerifyBatchTimeouts(4*time.Millisecond, 0); len(verdict) != 0 {
+		t.Fatalf("Expected the TTC message posted in time to pass, got: %s", verdict[0].Message())
+	}
+	if verdict := v.VerifyBatchTimeouts(5*time.Millisecond, time.Millisecond); len(verdict) != 0 {
+		t.Fatalf("Expected the TTC message within the tolerance to pass, got: %s", verdict[0].Message())
+	}
+
+	verdict := v.VerifyBatchTimeouts(10*time.Millisecond, time.Millisecond)
+	if len(verdict) != 1 || !strings.HasPrefix(verdict[0].Message(), "Ordering service posted a premature TTC message at offset 4 for block 1") {
+		t.Fatalf("Expected the premature TTC message to be detected, got %v", verdict)
+	}
+	if measured, expected := verdict[0].Delays(); measured != 4*time.Millisecond || expected != 10*time.Millisecond || verdict[0].Type() != verdicts.ORDERING_SERVICE_VERDICT {
+		t.Fatalf("Expected a verdict against the ordering service with the measured delay, got %s after %s instead of %s", verdict[0].Identity(), measured, expected)
+	}
+
+	verdict = v.VerifyBatchTimeouts(time.Millisecond, time.Millisecond)
+	if len(verdict) != 1 || !strings.HasPrefix(verdict[0].Message(), "Ordering service withheld the TTC message at offset 4 for block 1") {
+		t.Fatalf("Expected the withheld TTC message to be detected, got %v", verdict)
+	}
+}
@@ -0,0 +1,85 @@
+package verifier
+
+import (
+	"fmt"
+
+	kf "github.com/hyperledger/fabric_judge/protos/kafka"
+	verdicts "github.com/hyperledger/fabric_judge/verdicts"
+)
+
+// VerifyConnectAndTTCMessages decodes the TTC and connect messages of every block and checks their semantics:
+// The TTC message, which cut a block, must carry the number of this block, whereas all other TTC messages must have been ignored by the orderer.
+// Connect messages must not carry a payload
+func (v *Verifier) VerifyConnectAndTTCMessages() []*verdicts.Verdict {
+	// starting at i = 1, since the genesis block is not cut by the orderer
+	for i := 1; i < len(v.KafkaMetadata); i++ {
+		metadata := v.KafkaMetadata[i]
+		blockNumber := v.BlockNumbers[i]
+
+		if metadata.ReceivedTTCMessage != (metadata.TTCPayload != nil) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer set ReceivedTTCMessage of block %d inconsistently to its TTC payload", blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+		if metadata.ReceivedConnectOrTTCMessage != (len(metadata.ConnectOrTTCPayload) > 0) {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer set ReceivedConnectOrTTCMessage of block %d inconsistently to its connect or TTC payloads", blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+
+		if metadata.TTCPayload != nil {
+			consumerMessage, err := DecodeConsumerMessage(metadata.TTCPayload.ConsumerMessageBytes)
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d with an undecodable TTC message: %s", blockNumber, err), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			ttc := consumerMessage.Message.GetTimeToCut()
+			if ttc == nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d with a Kafka message at offset %d, which is not a TTC message", blockNumber, consumerMessage.Offset), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+			if ttc.BlockNumber != blockNumber {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d with the TTC message at offset %d for block %d", blockNumber, consumerMessage.Offset, ttc.BlockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+		}
+
+		for _, payload := range metadata.ConnectOrTTCPayload {
+			consumerMessage, err := DecodeConsumerMessage(payload.ConsumerMessageBytes)
+			if err != nil {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Metadata of block %d contains an undecodable connect or TTC message: %s", blockNumber, err), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+
+			verdict := v.verifyIgnoredMessage(consumerMessage, i)
+			if verdict != nil {
+				return verdict
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyIgnoredMessage checks that the connect or TTC message, which did not cut block i, was rightfully ignored by the orderer
+func (v *Verifier) verifyIgnoredMessage(consumerMessage *ConsumerMessage, i int) []*verdicts.Verdict {
+	blockNumber := v.BlockNumbers[i]
+
+	switch msg := consumerMessage.Message.Type.(type) {
+	case *kf.KafkaMessage_Connect:
+		if len(msg.Connect.Payload) > 0 {
+			return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Connect message at offset %d in block %d carries a payload", consumerMessage.Offset, blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+		}
+	case *kf.KafkaMessage_TimeToCut:
+		if msg.TimeToCut.BlockNumber != blockNumber {
+			// stale TTC messages (and TTC messages for future blocks) are ignored by the orderer
+			return nil
+		}
+		// a TTC message for the current block must cut the block, unless there were no pending messages at this point
+		for _, env := range v.Envelopes[i] {
+			// the offset of a redacted envelope is not covered by its leaf hash, thus it cannot prove that the message was pending
+			if _, redacted := v.RedactedLeaves[env]; redacted {
+				continue
+			}
+			if env.KafkaPayload != nil && env.KafkaPayload.KafkaOffset < consumerMessage.Offset {
+				return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Orderer ignored the TTC message at offset %d for block %d, although messages were pending", consumerMessage.Offset, blockNumber), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+			}
+		}
+	default:
+		return []*verdicts.Verdict{verdicts.CreateVerdict(fmt.Sprintf("Metadata of block %d contains the Kafka message at offset %d, which is neither a connect nor a TTC message", blockNumber, consumerMessage.Offset), v.Identity, 1).WithEvidence(v.Identity, blockNumber)}
+	}
+
+	return nil
+}
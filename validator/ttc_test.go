@@ -0,0 +1,46 @@
+package verifier_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric_judge/internal/ledgertest"
+)
+
+func TestVerifyConnectAndTTCMessages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ttc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	v := newKafkaVerifier(t, f, f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1, TTC: 1}}, {{Offset: 2}, {Offset: 3}}}))
+	if verdict := v.VerifyConnectAndTTCMessages(); len(verdict) != 0 {
+		t.Fatalf("Expected the block cut by its TTC message to pass, got: %s", verdict[0].Message())
+	}
+
+	// the flag of the metadata has to match the TTC payload
+	v.KafkaMetadata[1].ReceivedTTCMessage = false
+	verdict := v.VerifyConnectAndTTCMessages()
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer set ReceivedTTCMessage of block 1 inconsistently to its TTC payload" {
+		t.Fatalf("Expected the inconsistent metadata to be detected, got %v", verdict)
+	}
+}
+
+func TestVerifyTTCMessageOfOtherBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ttc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := ledgertest.NewKafkaFixture(t, dir)
+
+	// the stale TTC message for block 1 must not cut block 2
+	v := newKafkaVerifier(t, f, f.Chain([][]ledgertest.Message{{{Offset: 0}, {Offset: 1, TTC: 1}}, {{Offset: 2}, {Offset: 3, TTC: 1}}}))
+	verdict := v.VerifyConnectAndTTCMessages()
+	if len(verdict) != 1 || verdict[0].Message() != "Orderer cut block 2 with the TTC message at offset 3 for block 1" {
+		t.Fatalf("Expected the block cut by a stale TTC message to be detected, got %v", verdict)
+	}
+}
@@ -1,7 +1,10 @@
 package verifier
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"sort"
 
@@ -10,11 +13,55 @@ import (
 	kf "github.com/hyperledger/fabric_judge/protos/kafka"
 )
 
-// ValidateConnectOrTTCMessage checks the merkle proof and signature, in case the block contains a TTC message
-func ValidateConnectOrTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string, lastBlock bool) error {
+// ConsumerMessage is a decoded message, which Kafka signed and the orderer consumed from its partition
+type ConsumerMessage struct {
+	Offset    int64
+	Timestamp int64
+	Message   *kf.KafkaMessage
+}
+
+// DecodeConsumerMessage decodes the signed data of a consumer message, which consists of bytesOf(KafkaOffset) + bytesOf(KafkaTimestamp) + marshaled KafkaMessage
+func DecodeConsumerMessage(consumerMessageBytes []byte) (*ConsumerMessage, error) {
+	if len(consumerMessageBytes) < 16 {
+		return nil, fmt.Errorf("consumer message is too short (%d bytes)", len(consumerMessageBytes))
+	}
+
+	kafkaMessage := &kf.KafkaMessage{}
+	err := proto.Unmarshal(consumerMessageBytes[16:], kafkaMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsumerMessage{
+		Offset:    int64(binary.BigEndian.Uint64(consumerMessageBytes[0:8])),
+		Timestamp: int64(binary.BigEndian.Uint64(consumerMessageBytes[8:16])),
+		Message:   kafkaMessage,
+	}, nil
+}
+
+// RootSignatures caches the Kafka signatures of Merkle roots, which were already verified, by the hex encoded root hash.
+// Kafka signs the root once per batch, thus only the Merkle proofs of the further messages of a batch have to be verified
+type RootSignatures map[string][]byte
+
+// verify checks the Kafka signature of the root of the proof, unless the same signature of the root was verified before
+func (roots RootSignatures) verify(proof Proof, signature []byte, pkPath string) error {
+	rootHash := hex.EncodeToString(proof.RootHash)
+	if cached, ok := roots[rootHash]; ok && bytes.Equal(cached, signature) {
+		return nil
+	}
+	err := proof.VerifySignatureWithPath(signature, pkPath)
+	if err == nil && roots != nil {
+		roots[rootHash] = signature
+	}
+	return err
+}
+
+// ValidateConnectOrTTCMessage checks the merkle proof and signature, in case the block contains a TTC message.
+// Verified root signatures are cached in roots, which may be nil
+func ValidateConnectOrTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string, lastBlock bool, roots RootSignatures) error {
 	if len(kafkaMetadata.ConnectOrTTCPayload) > 0 {
 		for _, payload := range kafkaMetadata.ConnectOrTTCPayload {
-			err := validatePayload(payload, pkPath, lastBlock)
+			err := validatePayload(payload, pkPath, lastBlock, roots)
 			if err != nil {
 				return err
 			}
@@ -23,10 +70,11 @@ func ValidateConnectOrTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string,
 	return nil
 }
 
-// ValidateTTCMessage checks the merkle proof and signature, in case the block contains a TTC message
-func ValidateTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string, lastBlock bool) error {
+// ValidateTTCMessage checks the merkle proof and signature, in case the block contains a TTC message.
+// Verified root signatures are cached in roots, which may be nil
+func ValidateTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string, lastBlock bool, roots RootSignatures) error {
 	if kafkaMetadata.TTCPayload != nil {
-		err := validatePayload(kafkaMetadata.TTCPayload, pkPath, lastBlock)
+		err := validatePayload(kafkaMetadata.TTCPayload, pkPath, lastBlock, roots)
 		if err != nil {
 			return err
 		}
@@ -34,7 +82,7 @@ func ValidateTTCMessage(kafkaMetadata *kf.KafkaMetadata, pkPath string, lastBloc
 	return nil
 }
 
-func validatePayload(payload *kf.KafkaPayload, pkPath string, lastBlock bool) error {
+func validatePayload(payload *kf.KafkaPayload, pkPath string, lastBlock bool, roots RootSignatures) error {
 	proof := GetProofFromBytes(payload.KafkaMerkleProofHeader)
 
 	//Verify Merkle Proof
@@ -47,7 +95,7 @@ func validatePayload(payload *kf.KafkaPayload, pkPath string, lastBlock bool) er
 	}
 
 	//Verify Signature
-	if proof.VerifySignatureWithPath(payload.KafkaSignatureHeader, pkPath) != nil {
+	if roots.verify(proof, payload.KafkaSignatureHeader, pkPath) != nil {
 		if !lastBlock {
 			return fmt.Errorf("Peer should not have accepted faulty block (metadata signature is invalid). Furthermore, the orderer should not have forwarded this block in the first case")
 		} else {
@@ -62,51 +110,20 @@ func validatePayload(payload *kf.KafkaPayload, pkPath string, lastBlock bool) er
 func VerifyTransaction(env *cb.Envelope, tIdx int, pkPath string, lastBlock bool) error {
 
 	if env.KafkaPayload != nil {
-		proof := GetProofFromBytes(env.KafkaPayload.KafkaMerkleProofHeader)
-
-		// Rebuild Kafkas Signed Data
-		/*
-		* In the Following we describe, how we rebuild the signed data:
-		*
-		* Kafka signs the ConsumerMessages Payload, which is a marshaled KafkaMessage.
-		* The Orderer can cast this KafkaMessage to a KafkaMessageRegular which contains the Payload of the marshaled Envelope and other fields.
-		* To avoid redundancy, we marshal the sent Envelope (!! without the newly added KafkaPayload !!) to regain the Payload of the KafkaMessageRegular.
-		 */
-		oldEnv := &cb.Envelope{
-			Payload:              env.Payload,
-			Signature:            env.Signature,
-			XXX_NoUnkeyedLiteral: env.XXX_NoUnkeyedLiteral,
-			XXX_unrecognized:     env.XXX_unrecognized,
-			XXX_sizecache:        env.XXX_sizecache,
-		}
-
-		regMessagePayload, _ := proto.Marshal(oldEnv)
-
-		kafkaMessage := &kf.KafkaMessage{
-			Type: &kf.KafkaMessage_Regular{
-				Regular: &kf.KafkaMessageRegular{
-					Payload:              regMessagePayload,
-					ConfigSeq:            env.KafkaPayload.KafkaRegularMessage.ConfigSeq,
-					Class:                kf.KafkaMessageRegular_Class(env.KafkaPayload.KafkaRegularMessage.Class),
-					OriginalOffset:       env.KafkaPayload.KafkaRegularMessage.OriginalOffset,
-					XXX_NoUnkeyedLiteral: env.KafkaPayload.KafkaRegularMessage.XXX_NoUnkeyedLiteral,
-					XXX_unrecognized:     env.KafkaPayload.KafkaRegularMessage.XXX_unrecognized,
-					XXX_sizecache:        env.KafkaPayload.KafkaRegularMessage.XXX_sizecache,
-				},
-			},
-		}
-
-		marshaledData, _ := proto.Marshal(kafkaMessage)
-
-		//the signed data consists of bytesOf(KafkaOffset) + bytesOf(KafkaTimestamp) + marshaledData
+		leafHash := sha256.Sum256(GetKafkaSignedDataFromEnvelope(env))
+		return verifyTransactionOfLeafHash(env, leafHash[:], pkPath, lastBlock, nil)
+	}
+	return nil
+}
 
-		kafkaSignedData := make([]byte, 16)
-		binary.BigEndian.PutUint64(kafkaSignedData[0:8], uint64(env.KafkaPayload.KafkaOffset))
-		binary.BigEndian.PutUint64(kafkaSignedData[8:16], uint64(env.KafkaPayload.KafkaTimestamp))
-		kafkaSignedData = append(kafkaSignedData, marshaledData...)
+// verifyTransactionOfLeafHash checks the Kafka merkle proof and signature of the envelope against the hash of the data signed by Kafka.
+// Verified root signatures are cached in roots, which may be nil
+func verifyTransactionOfLeafHash(env *cb.Envelope, leafHash []byte, pkPath string, lastBlock bool, roots RootSignatures) error {
+	if env.KafkaPayload != nil {
+		proof := GetProofFromBytes(env.KafkaPayload.KafkaMerkleProofHeader)
 
 		//Verify Merkle Proof
-		if !proof.VerifyProof(kafkaSignedData) {
+		if !proof.VerifyProofOfLeafHash(leafHash) {
 			if !lastBlock {
 				return fmt.Errorf("Peer should have not accepted blocks containing an invalid Merkle Proof. Furthermore, the orderer should not have forwarded a transaction with an invalid merkle proof")
 			} else {
@@ -115,7 +132,7 @@ func VerifyTransaction(env *cb.Envelope, tIdx int, pkPath string, lastBlock bool
 		}
 
 		//Verify Signature
-		if proof.VerifySignatureWithPath(env.KafkaPayload.KafkaSignatureHeader, pkPath) != nil {
+		if roots.verify(proof, env.KafkaPayload.KafkaSignatureHeader, pkPath) != nil {
 			if !lastBlock {
 				return fmt.Errorf("Peer should have not accepted blocks containing an invalid Kafka signature. Furthermore, the orderer should not have forwarded a transaction with an invalid Kafka signature")
 			} else {
@@ -126,6 +143,123 @@ func VerifyTransaction(env *cb.Envelope, tIdx int, pkPath string, lastBlock bool
 	return nil
 }
 
+// GetPayloadFromEnvelope unmarshals the payload of the envelope and ensures that it contains a header
+func GetPayloadFromEnvelope(env *cb.Envelope) (*cb.Payload, error) {
+	payload := &cb.Payload{}
+	err := proto.Unmarshal(env.Payload, payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, fmt.Errorf("payload does not contain a header")
+	}
+	return payload, nil
+}
+
+// GetChannelHeaderFromEnvelope unmarshals the payload of the envelope and returns its channel header
+func GetChannelHeaderFromEnvelope(env *cb.Envelope) (*cb.ChannelHeader, error) {
+	payload, err := GetPayloadFromEnvelope(env)
+	if err != nil {
+		return nil, err
+	}
+
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	if err != nil {
+		return nil, err
+	}
+	return channelHeader, nil
+}
+
+// GetSignatureHeaderFromEnvelope unmarshals the payload of the envelope and returns its signature header
+func GetSignatureHeaderFromEnvelope(env *cb.Envelope) (*cb.SignatureHeader, error) {
+	payload, err := GetPayloadFromEnvelope(env)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureHeader := &cb.SignatureHeader{}
+	err = proto.Unmarshal(payload.Header.SignatureHeader, signatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return signatureHeader, nil
+}
+
+// ComputeTxID derives the transaction id from the nonce and the creator, as done by Fabric
+func ComputeTxID(nonce []byte, creator []byte) string {
+	h := sha256.New()
+	h.Write(nonce)
+	h.Write(creator)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// GetLastConfigIndexFromBlock retrieves the index of the last config block from the LAST_CONFIG metadata of the given block
+func GetLastConfigIndexFromBlock(block *cb.Block) (uint64, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_LAST_CONFIG) {
+		return 0, fmt.Errorf("block does not contain LAST_CONFIG metadata")
+	}
+
+	metadata := &cb.Metadata{}
+	err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG], metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	lastConfig := &cb.LastConfig{}
+	err = proto.Unmarshal(metadata.Value, lastConfig)
+	if err != nil {
+		return 0, err
+	}
+	return lastConfig.Index, nil
+}
+
+// GetKafkaSignedDataFromEnvelope rebuilds the data, which Kafka signed for the given envelope
+func GetKafkaSignedDataFromEnvelope(env *cb.Envelope) []byte {
+	// Rebuild Kafkas Signed Data
+	/*
+	* In the Following we describe, how we rebuild the signed data:
+	*
+	* Kafka signs the ConsumerMessages Payload, which is a marshaled KafkaMessage.
+	* The Orderer can cast this KafkaMessage to a KafkaMessageRegular which contains the Payload of the marshaled Envelope and other fields.
+	* To avoid redundancy, we marshal the sent Envelope (!! without the newly added KafkaPayload !!) to regain the Payload of the KafkaMessageRegular.
+	 */
+	oldEnv := &cb.Envelope{
+		Payload:              env.Payload,
+		Signature:            env.Signature,
+		XXX_NoUnkeyedLiteral: env.XXX_NoUnkeyedLiteral,
+		XXX_unrecognized:     env.XXX_unrecognized,
+		XXX_sizecache:        env.XXX_sizecache,
+	}
+
+	regMessagePayload, _ := proto.Marshal(oldEnv)
+
+	kafkaMessage := &kf.KafkaMessage{
+		Type: &kf.KafkaMessage_Regular{
+			Regular: &kf.KafkaMessageRegular{
+				Payload:              regMessagePayload,
+				ConfigSeq:            env.KafkaPayload.KafkaRegularMessage.ConfigSeq,
+				Class:                kf.KafkaMessageRegular_Class(env.KafkaPayload.KafkaRegularMessage.Class),
+				OriginalOffset:       env.KafkaPayload.KafkaRegularMessage.OriginalOffset,
+				XXX_NoUnkeyedLiteral: env.KafkaPayload.KafkaRegularMessage.XXX_NoUnkeyedLiteral,
+				XXX_unrecognized:     env.KafkaPayload.KafkaRegularMessage.XXX_unrecognized,
+				XXX_sizecache:        env.KafkaPayload.KafkaRegularMessage.XXX_sizecache,
+			},
+		},
+	}
+
+	marshaledData, _ := proto.Marshal(kafkaMessage)
+
+	//the signed data consists of bytesOf(KafkaOffset) + bytesOf(KafkaTimestamp) + marshaledData
+
+	kafkaSignedData := make([]byte, 16)
+	binary.BigEndian.PutUint64(kafkaSignedData[0:8], uint64(env.KafkaPayload.KafkaOffset))
+	binary.BigEndian.PutUint64(kafkaSignedData[8:16], uint64(env.KafkaPayload.KafkaTimestamp))
+	kafkaSignedData = append(kafkaSignedData, marshaledData...)
+
+	return kafkaSignedData
+}
+
 // GetKafkaSeqNrFromEnvelope retrieves the sequence number of the given envelope
 func GetKafkaSeqNrFromEnvelope(env *cb.Envelope) int64 {
 	if env.KafkaPayload == nil {
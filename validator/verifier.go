@@ -1,7 +1,8 @@
 package verifier
 
 import (
-	"log"
+	"crypto/sha256"
+	"fmt"
 
 	proto "github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric_judge/protos/common"
@@ -11,117 +12,176 @@ import (
 
 // Verifier contains the envelopes and metadata of the blocks
 type Verifier struct {
+	Blocks            []*cb.Block
 	Envelopes         [][]*cb.Envelope
 	KafkaMetadata     []*kf.KafkaMetadata
+	BlockNumbers      []uint64
 	Identity          string
 	PreferredMaxBytes int
 	MaxBatchSize      int
+	AbsoluteMaxBytes  int
+	MessageSizeBytes  SizeAccounting
 	pkPath            string
+
+	// RedactedLeaves contains the Kafka leaf hashes of the envelopes, whose payload was redacted from an evidence bundle.
+	// Since the data signed by Kafka cannot be rebuilt for them, their merkle proofs are verified against the leaf hash
+	RedactedLeaves map[*cb.Envelope][]byte
+
+	// ExpectedOffsets contains the Kafka offset, with which the sequence has to continue at the first block after a gap,
+	// e.g. the offset recorded in the checkpoint of a previous run. Without it, the sequence restarts with the lowest offset of the block
+	ExpectedOffsets map[uint64]int64
+	// ResumedEnvelopes and ResumedMerkleLeaves are the envelopes and the leaves of the open Merkle batches of the blocks before the checkpoint,
+	// from which the ledger is resumed. The checks, which compare messages across the ledger, include them
+	ResumedEnvelopes    []*EnvelopeRecord
+	ResumedMerkleLeaves []*MerkleLeafRecord
+	// RootSignatures caches the verified Kafka signatures of Merkle roots
+	RootSignatures RootSignatures
+	// Progress receives the notices of the checks, which do not render a verdict on their own. If it is nil, they are dropped
+	Progress func(message string)
 }
 
 // NewVerifier extracts the envelopes and metadata from the given blocks
-func NewVerifier(blocks []*cb.Block, pkPath string, identity string, maxBatchSize int, preferredMaxBytes int) *Verifier {
+func NewVerifier(blocks []*cb.Block, pkPath string, identity string, maxBatchSize int, preferredMaxBytes int, absoluteMaxBytes int, sizeAccounting SizeAccounting) (*Verifier, error) {
 	verifier := &Verifier{
+		Blocks:            blocks,
 		Envelopes:         make([][]*cb.Envelope, 0),
 		KafkaMetadata:     make([]*kf.KafkaMetadata, 0),
+		BlockNumbers:      make([]uint64, 0),
 		pkPath:            pkPath,
 		Identity:          identity,
 		PreferredMaxBytes: preferredMaxBytes,
 		MaxBatchSize:      maxBatchSize,
+		AbsoluteMaxBytes:  absoluteMaxBytes,
+		MessageSizeBytes:  sizeAccounting,
+		RedactedLeaves:    make(map[*cb.Envelope][]byte),
+		ExpectedOffsets:   make(map[uint64]int64),
+		RootSignatures:    make(RootSignatures),
 	}
 
 	for _, block := range blocks {
-		verifier.getEnvelopesOfBlock(block)
-		verifier.getMetadataOfBlock(block)
+		err := verifier.getEnvelopesOfBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		err = verifier.getMetadataOfBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		verifier.BlockNumbers = append(verifier.BlockNumbers, block.Header.Number)
 	}
 
-	return verifier
+	return verifier, nil
+}
+
+func (v *Verifier) progress(message string) {
+	if v.Progress != nil {
+		v.Progress(message)
+	}
 }
 
 // VerifyKafkaMessages Iterates over all Envelopes (of all blocks) and verifies the Kafka merkleproofs and signatures
 func (v *Verifier) VerifyKafkaMessages() []*verdicts.Verdict {
+	verdict := v.VerifyKafkaSignatures()
+	if verdict != nil {
+		return verdict
+	}
+
+	return v.verifyKafkaSequence()
+}
+
+// VerifyKafkaSignatures verifies the Kafka merkleproofs and signatures of all envelopes, TTC and connect messages, without checking their sequence
+func (v *Verifier) VerifyKafkaSignatures() []*verdicts.Verdict {
 	numberOfBlocks := len(v.Envelopes)
 	var err error
 	for i, metadata := range v.KafkaMetadata {
-		err = ValidateTTCMessage(metadata, v.pkPath, i == numberOfBlocks)
+		err = ValidateTTCMessage(metadata, v.pkPath, i == numberOfBlocks, v.RootSignatures)
 		if err != nil {
-			return evaluateError(err, i == numberOfBlocks)
+			return verdicts.AttachEvidence(evaluateError(err, i == numberOfBlocks), v.Identity, v.BlockNumbers[i])
 		}
-		err = ValidateConnectOrTTCMessage(metadata, v.pkPath, i == numberOfBlocks)
+		err = ValidateConnectOrTTCMessage(metadata, v.pkPath, i == numberOfBlocks, v.RootSignatures)
 		if err != nil {
-			return evaluateError(err, i == numberOfBlocks)
+			return verdicts.AttachEvidence(evaluateError(err, i == numberOfBlocks), v.Identity, v.BlockNumbers[i])
 		}
 	}
 
 	for i, blockEnv := range v.Envelopes {
 		for tIdx, env := range blockEnv {
-			err = VerifyTransaction(env, tIdx, v.pkPath, i == numberOfBlocks)
+			err = v.verifyTransaction(env, tIdx, i == numberOfBlocks)
 			if err != nil {
-				return evaluateError(err, i == numberOfBlocks)
+				return verdicts.AttachEvidence(evaluateError(err, i == numberOfBlocks), v.Identity, v.BlockNumbers[i])
 			}
 		}
 	}
 
-	return v.verifyKafkaSequence()
+	return nil
 }
 
-// VerifyBlockCuttingOfOrderer checks if the orderer followed the specified Block-Cutting algorithm
+// VerifyBlockCuttingOfOrderer checks if the orderer followed the specified Block-Cutting algorithm.
+// The Kafka stream is rebuilt from the ledger and replayed through the BlockCutter, such that the expected blocks can be compared with the actual ones
 func (v *Verifier) VerifyBlockCuttingOfOrderer() []*verdicts.Verdict {
-	// starting at i = 1, since genesis block is handled seperately
-	for i := 1; i < len(v.Envelopes); i++ {
-		if v.KafkaMetadata[i].IsConfigMessage {
-			// config messages are isolated
-			continue
-		}
+	stream, err := v.RebuildKafkaStream()
+	if err != nil {
+		return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), v.Identity, 1)}
+	}
 
-		var blockSize int
-		blockSize = 0
-		for _, env := range v.Envelopes[i] {
-			blockSize += messageSizeBytes(env)
-		}
+	expected, pending := v.SimulateBlockCutting(stream)
+	diffs, unverifiableLastBlock := v.DiffBlockCutting(expected, pending)
 
-		if blockSize > v.PreferredMaxBytes || len(v.Envelopes[i]) > v.MaxBatchSize {
-			if len(v.Envelopes[i]) == 1 {
-				// message was isolated as specified
-				continue
-			} else {
-				// otherwise, the orderer cut the block to late
-				return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer cut the block too late", v.Identity, 1)}
-			}
-		}
+	if unverifiableLastBlock {
+		v.progress("The last block of " + v.Identity + " contains all pending messages, the cause of its cut is not part of the ledger")
+	}
 
-		if v.KafkaMetadata[i].ReceivedTTCMessage || len(v.Envelopes[i]) == v.MaxBatchSize {
-			// we already verified, that the TTC message has the correct kafka sequence number
-			// thus the orderer is right, to cut the block at this point
-			continue
-		}
+	if len(diffs) == 0 {
+		return nil
+	}
 
-		// the only remaining possibility for a cut is, if the next envelope would exceed the preferredmaxbytes bound
-		if i == len(v.Envelopes)-1 {
-			// in this case we are unable to verify, whether the orderer was right to cut the block here
-			return nil
-		}
+	v.progress("Block-by-block diff of " + v.Identity + ":")
+	for _, diff := range diffs {
+		v.progress("\t" + diff.String())
+	}
 
-		nextEnvSize := messageSizeBytes(v.Envelopes[i+1][0])
-		if blockSize+nextEnvSize > v.PreferredMaxBytes {
-			// again, the orderer was right to cut here
-			continue
-		} else if v.KafkaMetadata[i+1].IsConfigMessage {
-			// if the orderer sends a config message, the pending block is cut
-			continue
-		}
-		// the orderer could have included the next envelope in this block but did not do so
-		return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer cut the block too early", v.Identity, 1)}
+	first := diffs[0]
+	verdict := verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d differently than the Block-Cutting algorithm", first.Number), v.Identity, 1)
+	if first.Number != first.ExpectedNumber && EqualOffsets(first.Expected, first.Actual) {
+		verdict = verdicts.CreateVerdict(fmt.Sprintf("Orderer numbered block %d, which the Block-Cutting algorithm cut as block %d", first.Number, first.ExpectedNumber), v.Identity, 1)
+	} else if len(first.Actual) < len(first.Expected) && EqualOffsets(first.Actual, first.Expected[:len(first.Actual)]) {
+		verdict = verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d too early", first.Number), v.Identity, 1)
+	} else if len(first.Actual) > len(first.Expected) && EqualOffsets(first.Expected, first.Actual[:len(first.Expected)]) {
+		verdict = verdicts.CreateVerdict(fmt.Sprintf("Orderer cut block %d too late", first.Number), v.Identity, 1)
 	}
 
-	return nil
+	// the previous block was cut correctly, such that the cut can be replayed starting with it. The next block is needed to show where the cut should have been
+	return []*verdicts.Verdict{verdict.WithEvidence(v.Identity, v.blockNumbersAround(first.Number)...)}
 }
 
 func (v *Verifier) verifyKafkaSequence() []*verdicts.Verdict {
 	var kafkaSeqNr, seqNr int64
 	kafkaSeqNr = 0
+	// lastBlock is the index of the block, which contains the previous Kafka message
+	lastBlock := 0
+	// resumedBlock is the index of the first block after a gap, whose expected offset is known. The Kafka message preceding it
+	// is contained in the skipped block before it, which is referenced as evidence as well
+	resumedBlock := -1
+	evidence := func(i int) []uint64 {
+		if lastBlock == resumedBlock {
+			return append([]uint64{v.BlockNumbers[lastBlock] - 1}, v.BlockNumbers[lastBlock:i+1]...)
+		}
+		return v.BlockNumbers[lastBlock : i+1]
+	}
 
 	for i := 0; i < len(v.Envelopes); i++ {
+		// a ledger of an evidence bundle or a resumed run may skip blocks, thus the sequence restarts after the gap
+		if i > 0 && v.BlockNumbers[i] != v.BlockNumbers[i-1]+1 {
+			if expected, ok := v.ExpectedOffsets[v.BlockNumbers[i]]; ok {
+				kafkaSeqNr = expected
+				lastBlock = i
+				resumedBlock = i
+			} else if lowest := v.lowestOffsetOfBlock(i); lowest != -1 {
+				kafkaSeqNr = lowest
+				lastBlock = i
+			}
+		}
+
 		connectOrTTCOffsets := GetAllConnectOrTTCKafkaSeqNrFromMetadata(v.KafkaMetadata[i])
 
 		for _, env := range v.Envelopes[i] {
@@ -137,56 +197,145 @@ func (v *Verifier) verifyKafkaSequence() []*verdicts.Verdict {
 				}
 				if seqNr != kafkaSeqNr {
 					if i == len(v.Envelopes)-1 {
-						return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1)}
+						return verdicts.AttachEvidence([]*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1)}, v.Identity, evidence(i)...)
 					}
-					return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1), verdicts.CreateVerdict("Peer accepted invalid block without reporting", v.Identity, 1)}
+					return verdicts.AttachEvidence([]*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1), verdicts.CreateVerdict("Peer accepted invalid block without reporting", v.Identity, 1)}, v.Identity, evidence(i)...)
 				}
 				kafkaSeqNr++
+				lastBlock = i
 			}
 		}
 		seqNr = GetTTCKafkaSeqNrFromMetadata(v.KafkaMetadata[i])
 		if seqNr != -1 {
 			if seqNr != kafkaSeqNr {
 				if i == len(v.Envelopes)-1 {
-					return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1)}
+					return verdicts.AttachEvidence([]*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1)}, v.Identity, evidence(i)...)
 				}
-				return []*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1), verdicts.CreateVerdict("Peer accepted invalid block without reporting", v.Identity, 1)}
+				return verdicts.AttachEvidence([]*verdicts.Verdict{verdicts.CreateVerdict("Orderer skipped Kafka messages", v.Identity, 1), verdicts.CreateVerdict("Peer accepted invalid block without reporting", v.Identity, 1)}, v.Identity, evidence(i)...)
 			}
 			kafkaSeqNr++
+			lastBlock = i
 		}
 	}
 
 	return nil
 }
 
-func (v *Verifier) getEnvelopesOfBlock(block *cb.Block) {
-	var err error
+// lowestOffsetOfBlock returns the lowest Kafka offset of all envelopes, TTC and connect messages of block i, or -1 if it does not contain any Kafka message
+func (v *Verifier) lowestOffsetOfBlock(i int) int64 {
+	offsets := make([]int64, 0)
+	for _, env := range v.Envelopes[i] {
+		offsets = append(offsets, GetKafkaSeqNrFromEnvelope(env))
+	}
+	offsets = append(offsets, GetTTCKafkaSeqNrFromMetadata(v.KafkaMetadata[i]))
+	for _, offset := range GetAllConnectOrTTCKafkaSeqNrFromMetadata(v.KafkaMetadata[i]) {
+		offsets = append(offsets, int64(offset))
+	}
+
+	lowest := int64(-1)
+	for _, offset := range offsets {
+		if offset != -1 && (lowest == -1 || offset < lowest) {
+			lowest = offset
+		}
+	}
+	return lowest
+}
+
+// blockNumbersAround returns the numbers of the blocks of the ledger before, at and after the given block number
+func (v *Verifier) blockNumbersAround(number uint64) []uint64 {
+	numbers := make([]uint64, 0, 3)
+	for _, blockNumber := range v.BlockNumbers {
+		if blockNumber != 0 && blockNumber+1 >= number && blockNumber <= number+1 {
+			numbers = append(numbers, blockNumber)
+		}
+	}
+	return numbers
+}
+
+// IsSignedByKafka checks whether the given envelope carries a valid Kafka merkle proof and signature
+func (v *Verifier) IsSignedByKafka(env *cb.Envelope) bool {
+	return env.KafkaPayload != nil && v.verifyTransaction(env, 0, false) == nil
+}
+
+// verifyTransaction checks the Kafka merkle proof and signature of the envelope, using the leaf hash of redacted envelopes
+func (v *Verifier) verifyTransaction(env *cb.Envelope, tIdx int, lastBlock bool) error {
+	if leafHash, ok := v.RedactedLeaves[env]; ok {
+		return verifyTransactionOfLeafHash(env, leafHash, v.pkPath, lastBlock, v.RootSignatures)
+	}
+	if env.KafkaPayload != nil {
+		leafHash := sha256.Sum256(GetKafkaSignedDataFromEnvelope(env))
+		return verifyTransactionOfLeafHash(env, leafHash[:], v.pkPath, lastBlock, v.RootSignatures)
+	}
+	return nil
+}
+
+// NextKafkaOffset returns the Kafka offset, which follows the highest offset consumed into the ledger
+func (v *Verifier) NextKafkaOffset() (int64, error) {
+	highestOffset := int64(-1)
+	for i := range v.KafkaMetadata {
+		offset, err := v.highestConsumedOffset(i)
+		if err != nil {
+			return 0, err
+		}
+		if offset > highestOffset {
+			highestOffset = offset
+		}
+	}
+	return highestOffset + 1, nil
+}
+
+// PendingOffsets returns the offsets of the messages, which the Block-Cutting algorithm has not cut at the end of the ledger.
+// These messages were cut into the last block for a cause, which is not part of the ledger yet
+func (v *Verifier) PendingOffsets() ([]int64, error) {
+	stream, err := v.RebuildKafkaStream()
+	if err != nil {
+		return nil, err
+	}
+	_, pending := v.SimulateBlockCutting(stream)
+	return offsetsOf(pending), nil
+}
+
+// KafkaLeafHash returns the hash of the data, which Kafka signed for the envelope
+func (v *Verifier) KafkaLeafHash(env *cb.Envelope) []byte {
+	if leafHash, ok := v.RedactedLeaves[env]; ok {
+		return leafHash
+	}
+	leafHash := sha256.Sum256(GetKafkaSignedDataFromEnvelope(env))
+	return leafHash[:]
+}
+
+func (v *Verifier) getEnvelopesOfBlock(block *cb.Block) error {
 	blockEnv := make([]*cb.Envelope, 0)
-	for _, data := range block.Data.Data {
+	for tIdx, data := range block.Data.Data {
 		env := new(cb.Envelope)
-		err = proto.Unmarshal(data, env)
+		err := proto.Unmarshal(data, env)
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("Unable to unmarshal envelope %d of block %d of %s: %s", tIdx, block.Header.Number, v.Identity, err)
 		}
 		blockEnv = append(blockEnv, env)
 	}
 	v.Envelopes = append(v.Envelopes, blockEnv)
+	return nil
 }
 
-func (v *Verifier) getMetadataOfBlock(block *cb.Block) {
+func (v *Verifier) getMetadataOfBlock(block *cb.Block) error {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_ORDERER) {
+		return fmt.Errorf("Block %d of %s does not contain ORDERER metadata", block.Header.Number, v.Identity)
+	}
 	ordererMetadata := &cb.Metadata{}
 	err := proto.Unmarshal(block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER], ordererMetadata)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("Unable to unmarshal ORDERER metadata of block %d of %s: %s", block.Header.Number, v.Identity, err)
 	}
 
 	kafkaMetadata := &kf.KafkaMetadata{}
 	err = proto.Unmarshal(ordererMetadata.Value, kafkaMetadata)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("Unable to unmarshal KafkaMetadata of block %d of %s: %s", block.Header.Number, v.Identity, err)
 	}
 
 	v.KafkaMetadata = append(v.KafkaMetadata, kafkaMetadata)
+	return nil
 }
 
 func evaluateError(err error, lastBlock bool) []*verdicts.Verdict {
@@ -195,7 +344,3 @@ func evaluateError(err error, lastBlock bool) []*verdicts.Verdict {
 	}
 	return []*verdicts.Verdict{verdicts.CreateVerdict(err.Error(), "Orderer", 1), verdicts.CreateVerdict(err.Error(), "Peer", 2)}
 }
-
-func messageSizeBytes(message *cb.Envelope) int {
-	return len(message.Payload) + len(message.Signature) + len(message.KafkaPayload.KafkaMerkleProofHeader) + len(message.KafkaPayload.KafkaSignatureHeader) + 1
-}
@@ -1,6 +1,9 @@
 package verdicts
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type VerdictType int16
 
@@ -8,15 +11,33 @@ const KAFKA_VERDICT = 0
 const ORDERER_VERDICT = 1
 const PEER_VERDICT = 2
 
+// ORDERING_SERVICE_VERDICT blames the ordering service as a whole, if the misbehaving orderer cannot be determined from the ledgers
+const ORDERING_SERVICE_VERDICT = 3
+
+// TIMING_CATEGORY marks verdicts which are based on the Kafka timestamps of the ordered messages
+const TIMING_CATEGORY = "timing"
+
 type Verdict struct {
 	verdict  string
 	identity string
+	category string
 
 	verdictType VerdictType
+
+	measuredDelay time.Duration
+	expectedDelay time.Duration
+
+	evidence []*Evidence
+}
+
+// Evidence references the blocks of a single ledger, which are needed to reproduce a verdict
+type Evidence struct {
+	Ledger string
+	Blocks []uint64
 }
 
 func CreateVerdict(verdict string, identity string, verdictType VerdictType) *Verdict {
-	if verdict == "" || verdictType < 0 || verdictType > 2 {
+	if verdict == "" || verdictType < 0 || verdictType > 3 {
 		return nil
 	}
 	if identity == "" && verdictType != 0 {
@@ -29,13 +50,80 @@ func CreateVerdict(verdict string, identity string, verdictType VerdictType) *Ve
 	}
 }
 
+// CreateTimingVerdict creates a verdict against the ordering service, which contains the measured and the expected delay of a time-to-cut message.
+// Every orderer of the channel may post TTC messages to Kafka without recording its identity, thus a late or premature one cannot be blamed on a single orderer
+func CreateTimingVerdict(verdict string, measuredDelay time.Duration, expectedDelay time.Duration) *Verdict {
+	v := CreateVerdict(verdict, "Ordering Service", ORDERING_SERVICE_VERDICT)
+	if v == nil {
+		return nil
+	}
+	v.category = TIMING_CATEGORY
+	v.measuredDelay = measuredDelay
+	v.expectedDelay = expectedDelay
+	return v
+}
+
+// WithEvidence references the given blocks of the ledger as evidence of the verdict and returns the verdict
+func (v *Verdict) WithEvidence(ledger string, blocks ...uint64) *Verdict {
+	if v == nil {
+		return nil
+	}
+	v.evidence = append(v.evidence, &Evidence{Ledger: ledger, Blocks: append([]uint64(nil), blocks...)})
+	return v
+}
+
+// AttachEvidence references the given blocks of the ledger as evidence of all verdicts
+func AttachEvidence(verdicts []*Verdict, ledger string, blocks ...uint64) []*Verdict {
+	for _, v := range verdicts {
+		v.WithEvidence(ledger, blocks...)
+	}
+	return verdicts
+}
+
+// Evidence returns the blocks, which are referenced as evidence of the verdict
+func (v *Verdict) Evidence() []*Evidence {
+	return v.evidence
+}
+
+// Message returns the reason of the verdict
+func (v *Verdict) Message() string {
+	return v.verdict
+}
+
+// Identity returns the identity, against which the verdict is rendered
+func (v *Verdict) Identity() string {
+	return v.identity
+}
+
+// Type returns whether the verdict is rendered against the Kafka Cluster, an orderer, a peer or the ordering service
+func (v *Verdict) Type() VerdictType {
+	return v.verdictType
+}
+
+// Category returns the category of the verdict, which is empty for ordinary verdicts
+func (v *Verdict) Category() string {
+	return v.category
+}
+
+// Delays returns the measured and the expected delay of a timing verdict
+func (v *Verdict) Delays() (time.Duration, time.Duration) {
+	return v.measuredDelay, v.expectedDelay
+}
+
 func (v *Verdict) EvaluateVerdict() string {
+	msg := v.verdict
+	if v.category == TIMING_CATEGORY {
+		msg = fmt.Sprintf("[%s] %s (measured delay: %s, expected delay: %s)", v.category, v.verdict, v.measuredDelay, v.expectedDelay)
+	}
+
 	if v.verdictType == 0 {
-		return fmt.Sprintf("VERDICT (KafkaCluster): %s", v.verdict)
+		return fmt.Sprintf("VERDICT (KafkaCluster): %s", msg)
 	} else if v.verdictType == 1 {
-		return fmt.Sprintf("VERDICT (Orderer of %s): %s", v.identity, v.verdict)
+		return fmt.Sprintf("VERDICT (Orderer of %s): %s", v.identity, msg)
 	} else if v.verdictType == 2 {
-		return fmt.Sprintf("VERDICT (%s): %s", v.identity, v.verdict)
+		return fmt.Sprintf("VERDICT (%s): %s", v.identity, msg)
+	} else if v.verdictType == 3 {
+		return fmt.Sprintf("VERDICT (OrderingService): %s", msg)
 	}
 	return ""
 }